@@ -0,0 +1,98 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aliases manages named aliases of Hugo versions, such as
+// "production" or "next".
+//
+// An alias is referenced as "@<name>" wherever a version/edition is accepted,
+// such as in the dot file. The alias is resolved when it is used, so changing
+// the version/edition of an alias changes it for every project that refers to
+// it.
+package aliases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Prefix is the prefix of a reference to an alias.
+const Prefix = "@"
+
+// nameRE matches a valid name of an alias.
+var nameRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ValidateName reports an error if name is not a valid name of an alias.
+func ValidateName(name string) error {
+	if !nameRE.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: must begin with a letter or digit, followed by up to 63 letters, digits, periods, hyphens, or underscores", name)
+	}
+	return nil
+}
+
+// Ref returns the reference to the alias name (e.g. @production).
+func Ref(name string) string {
+	return Prefix + name
+}
+
+// ParseRef returns the name of the alias referenced by s (e.g. @production),
+// or false if s does not reference an alias.
+func ParseRef(s string) (string, bool) {
+	name, ok := strings.CutPrefix(s, Prefix)
+	if !ok || ValidateName(name) != nil {
+		return "", false
+	}
+	return name, true
+}
+
+// A Registry maps the names of aliases to build identifiers
+// ("version/edition").
+type Registry map[string]string
+
+// Read reads the registry from the file at path. It returns an empty registry
+// if the file does not exist.
+func Read(path string) (Registry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Registry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r := Registry{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid registry of aliases in %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Write writes the registry to the file at path, creating its directory if
+// necessary.
+func (r Registry) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aliases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		wantName string
+		wantOK   bool
+	}{
+		{"@production", "production", true},
+		{"@legacy-docs", "legacy-docs", true},
+		{"@v0.150_1", "v0.150_1", true},
+		{"@", "", false},
+		{"@-next", "", false},
+		{"@a/b", "", false},
+		{"production", "", false},
+		{"v0.153.0/extended", "", false},
+	}
+	for _, tt := range tests {
+		name, ok := ParseRef(tt.ref)
+		if name != tt.wantName || ok != tt.wantOK {
+			t.Errorf("ParseRef(%q): want (%q, %v) got (%q, %v)", tt.ref, tt.wantName, tt.wantOK, name, ok)
+		}
+	}
+	if got := Ref("production"); got != "@production" {
+		t.Errorf("Ref: want %q got %q", "@production", got)
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hvm", "aliases.json")
+
+	r, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if len(r) != 0 {
+		t.Fatalf("Read(): want empty registry got %v", r)
+	}
+
+	r["production"] = "v0.150.1/extended"
+	if err := r.Write(path); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if got["production"] != "v0.150.1/extended" || len(got) != 1 {
+		t.Fatalf("round-trip: got %v", got)
+	}
+
+	if err := os.WriteFile(path, []byte("["), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Read(path); err == nil {
+		t.Fatal("Read() expected error for invalid file")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// arMagic is the global header at the start of an ar archive.
+const arMagic = "!<arch>\n"
+
+// arHeaderSize is the size of the header preceding each ar archive member.
+const arHeaderSize = 60
+
+// arHeader is the decoded header of an ar archive member.
+type arHeader struct {
+	Name string
+	Size int64
+}
+
+// arReader reads the members of an ar archive in the common, GNU, or BSD
+// format. Symbol tables and long name tables are returned like any other
+// member.
+type arReader struct {
+	r      *bufio.Reader
+	remain int64 // unread bytes of the current member's data
+	pad    int64 // bytes of padding after the current member's data
+}
+
+// newArReader returns an arReader reading from r, after verifying the global
+// header.
+func newArReader(r io.Reader) (*arReader, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != arMagic {
+		return nil, fmt.Errorf("not an ar archive")
+	}
+	return &arReader{r: br}, nil
+}
+
+// next advances to the next member, returning io.EOF at the end of the
+// archive.
+func (ar *arReader) next() (*arHeader, error) {
+	if _, err := io.CopyN(io.Discard, ar.r, ar.remain+ar.pad); err != nil {
+		return nil, err
+	}
+	ar.remain, ar.pad = 0, 0
+
+	buf := make([]byte, arHeaderSize)
+	if _, err := io.ReadFull(ar.r, buf); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated ar header")
+		}
+		return nil, err // io.EOF at a member boundary is the end of the archive
+	}
+	if string(buf[58:60]) != "`\n" {
+		return nil, fmt.Errorf("invalid ar header")
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(string(buf[48:58])), 10, 64)
+	if err != nil || size < 0 {
+		return nil, fmt.Errorf("invalid ar header")
+	}
+
+	// GNU ar terminates names with a slash; BSD ar pads them with spaces.
+	h := &arHeader{
+		Name: strings.TrimSuffix(strings.TrimSpace(string(buf[0:16])), "/"),
+		Size: size,
+	}
+	ar.remain = size
+	ar.pad = size % 2
+
+	// BSD ar stores long names at the start of the member's data.
+	if n, ok := strings.CutPrefix(h.Name, "#1/"); ok {
+		nameLen, err := strconv.ParseInt(n, 10, 64)
+		if err != nil || nameLen < 0 || nameLen > size || nameLen > maxCpioNameBytes {
+			return nil, fmt.Errorf("invalid ar header")
+		}
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(ar, name); err != nil {
+			return nil, err
+		}
+		h.Name = strings.TrimRight(string(name), "\x00")
+		h.Size = size - nameLen
+	}
+
+	return h, nil
+}
+
+// Read reads from the current member's data.
+func (ar *arReader) Read(p []byte) (int, error) {
+	if ar.remain <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > ar.remain {
+		p = p[:ar.remain]
+	}
+	n, err := ar.r.Read(p)
+	ar.remain -= int64(n)
+	if err == io.EOF && ar.remain > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
@@ -15,45 +15,43 @@ limitations under the License.
 */
 
 // Package archive implements routines for extracting archive files including
-// gzipped tarballs and zip files.
+// tarballs compressed with gzip, xz, or zstd, zip files, macOS packages, and
+// Debian packages.
 package archive
 
 import (
 	"errors"
-	"fmt"
 	"io/fs"
 	"os"
-	"strings"
 )
 
-// maxExtractBytes is the maximum number of bytes that may be written from a
-// single archive entry. This guards against decompression bombs.
-const maxExtractBytes = 500 << 20 // 500 MB
-
 // Extract unpacks an archive (src) into the destination directory (dst).
 // If rm is true, the source archive is deleted after successful extraction.
-// Supports macOS .pkg files, gzipped tarballs (.tar.gz), and .zip files.
-func Extract(src, dst string, rm bool) error {
-	srcLower := strings.ToLower(src)
+// If filter is not nil, only the entries it selects are extracted.
+// The archive format is detected from the content of the file rather than
+// its extension. Supports macOS .pkg files, Debian .deb files, .zip files, and
+// tarballs that are uncompressed or compressed with gzip, xz, or zstd. Use
+// Register to add support for other formats.
+func Extract(src, dst string, rm bool, filter Filter) error {
+	return ExtractWithLimits(src, dst, rm, filter, DefaultLimits)
+}
 
-	switch {
-	case strings.HasSuffix(srcLower, ".pkg"):
-		err := extractPkg(src, dst)
-		if err != nil {
-			return err
-		}
-	case strings.HasSuffix(srcLower, ".tar.gz"):
-		err := extractTarGZ(src, dst)
-		if err != nil {
-			return err
-		}
-	case strings.HasSuffix(srcLower, ".zip"):
-		err := extractZip(src, dst)
-		if err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("unknown archive format")
+// ExtractWithLimits is like Extract, but returns a *LimitError if extracting
+// the archive exceeds limits.
+func ExtractWithLimits(src, dst string, rm bool, filter Filter, limits Limits) error {
+	e, err := Detect(src)
+	if err != nil {
+		return err
+	}
+
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	err = e.Extract(src, dst, filter, NewLimiter(limits, fi.Size()))
+	if err != nil {
+		return err
 	}
 
 	if rm {
@@ -21,16 +21,24 @@ import (
 	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jmooring/hvm/helpers"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
 )
 
 // TestExtract tests the Extract function for various archive formats.
@@ -38,16 +46,15 @@ func TestExtract(t *testing.T) {
 	t.Parallel()
 
 	const (
-		pkgFileName   = "test.pkg"    // test extraction on darwin only
+		pkgFileName   = "test.pkg"    // test extraction on all operating systems
 		tarGZFileName = "test.tar.gz" // test extraction on all operating systems
 		zipFileName   = "test.zip"    // test extraction on all operating systems
 	)
 
 	var (
-		osAll    = []string{"darwin", "linux", "windows"}
-		osDarwin = []string{"darwin"}
-		dstDir   = t.TempDir()
-		srcDir   = t.TempDir()
+		osAll  = []string{"darwin", "linux", "windows"}
+		dstDir = t.TempDir()
+		srcDir = t.TempDir()
 	)
 
 	err := helpers.CopyDirectoryContent("testdata", srcDir)
@@ -67,8 +74,8 @@ func TestExtract(t *testing.T) {
 		args     args
 		wantErr  bool
 	}{
-		{"pkgKeep", osDarwin, args{filepath.Join(srcDir, pkgFileName), filepath.Clean(dstDir), false}, false},
-		{"pkgRemove", osDarwin, args{filepath.Join(srcDir, pkgFileName), filepath.Clean(dstDir), true}, false},
+		{"pkgKeep", osAll, args{filepath.Join(srcDir, pkgFileName), filepath.Clean(dstDir), false}, false},
+		{"pkgRemove", osAll, args{filepath.Join(srcDir, pkgFileName), filepath.Clean(dstDir), true}, false},
 		{"tarGZKeep", osAll, args{filepath.Join(srcDir, tarGZFileName), filepath.Clean(dstDir), false}, false},
 		{"tarGZRemove", osAll, args{filepath.Join(srcDir, tarGZFileName), filepath.Clean(dstDir), true}, false},
 		{"zipKeep", osAll, args{filepath.Join(srcDir, zipFileName), filepath.Clean(dstDir), false}, false},
@@ -81,7 +88,7 @@ func TestExtract(t *testing.T) {
 		}
 
 		t.Run(tt.name, func(t *testing.T) {
-			if err := Extract(tt.args.src, tt.args.dst, tt.args.rm); (err != nil) != tt.wantErr {
+			if err := Extract(tt.args.src, tt.args.dst, tt.args.rm, nil); (err != nil) != tt.wantErr {
 				t.Errorf("Extract() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -180,7 +187,7 @@ func TestExtractTarGZ_ZipSlip(t *testing.T) {
 		t.Fatalf("close file: %v", err)
 	}
 
-	if err := extractTarGZ(tarPath, dstDir); err == nil {
+	if err := extractTarGZ(tarPath, dstDir, nil, NewLimiter(DefaultLimits, 0)); err == nil {
 		t.Fatal("expected zip slip error for tar.gz")
 	}
 }
@@ -195,7 +202,7 @@ func TestExtractTarGZ_InvalidGzip(t *testing.T) {
 		t.Fatalf("write file: %v", err)
 	}
 
-	if err := extractTarGZ(tarPath, dstDir); err == nil {
+	if err := extractTarGZ(tarPath, dstDir, nil, NewLimiter(DefaultLimits, 0)); err == nil {
 		t.Fatal("expected error for invalid gzip")
 	}
 }
@@ -226,7 +233,7 @@ func TestExtractZip_ZipSlip(t *testing.T) {
 		t.Fatalf("close file: %v", err)
 	}
 
-	if err := extractZip(zipPath, dstDir); err == nil {
+	if err := extractZip(zipPath, dstDir, nil, NewLimiter(DefaultLimits, 0)); err == nil {
 		t.Fatal("expected zip slip error for zip")
 	}
 }
@@ -235,7 +242,1076 @@ func TestExtract_MissingFile(t *testing.T) {
 	t.Parallel()
 
 	missing := filepath.Join(t.TempDir(), "missing.tar.gz")
-	if err := Extract(missing, t.TempDir(), false); err == nil {
+	if err := Extract(missing, t.TempDir(), false, nil); err == nil {
 		t.Fatal("expected error for missing archive")
 	}
 }
+
+func TestExtractPkg_Newc(t *testing.T) {
+	t.Parallel()
+
+	var cpio bytes.Buffer
+	writeCpioNewc(t, &cpio, "./hugo", 0o100755, "hugo binary\n")
+	writeCpioNewc(t, &cpio, "./sub", 0o040755, "")
+	writeCpioNewc(t, &cpio, "./sub/LICENSE", 0o100644, "license\n")
+	writeCpioNewc(t, &cpio, "TRAILER!!!", 0, "")
+
+	pkgPath := filepath.Join(t.TempDir(), "newc.pkg")
+	writePkg(t, pkgPath, gzipBytes(t, cpio.Bytes()))
+
+	dstDir := t.TempDir()
+	if err := extractPkg(pkgPath, dstDir, nil, NewLimiter(DefaultLimits, 0)); err != nil {
+		t.Fatalf("extractPkg error: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dstDir, "sub", "LICENSE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "license\n" {
+		t.Fatalf("LICENSE: want %q got %q", "license\n", got)
+	}
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(filepath.Join(dstDir, "hugo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != 0o755 {
+			t.Fatalf("hugo mode: want 0755 got %v", fi.Mode().Perm())
+		}
+	}
+}
+
+func TestExtractPkg_Links(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links requires elevated privileges on Windows")
+	}
+	t.Parallel()
+
+	mtime := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	var cpio bytes.Buffer
+	writeCpioNewcAt(t, &cpio, "./d1", 0o040750, "", mtime)
+	writeCpioNewcAt(t, &cpio, "./d1/hugo", 0o100755, "hugo binary\n", mtime)
+	writeCpioNewcAt(t, &cpio, "./d1/LICENSE", 0o100600, "license\n", mtime)
+	writeCpioNewc(t, &cpio, "./hugo", 0o120777, "d1/hugo")
+	// A regular file that follows a symbolic link of the same name replaces
+	// the link instead of writing to its target.
+	writeCpioNewc(t, &cpio, "./LICENSE", 0o120777, "d1/LICENSE")
+	writeCpioNewcAt(t, &cpio, "./LICENSE", 0o100644, "replaced\n", mtime)
+	writeCpioNewc(t, &cpio, "TRAILER!!!", 0, "")
+
+	pkgPath := filepath.Join(t.TempDir(), "links.pkg")
+	writePkg(t, pkgPath, gzipBytes(t, cpio.Bytes()))
+
+	dst := t.TempDir()
+	if err := extractPkg(pkgPath, dst, nil, NewLimiter(DefaultLimits, 0)); err != nil {
+		t.Fatalf("extractPkg error: %v", err)
+	}
+
+	got, err := os.Readlink(filepath.Join(dst, "hugo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.FromSlash("d1/hugo"); got != want {
+		t.Errorf("hugo: link target: want %q got %q", want, got)
+	}
+	for name, want := range map[string]string{"LICENSE": "replaced\n", "d1/LICENSE": "license\n"} {
+		data, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s: want %q got %q", name, want, data)
+		}
+	}
+
+	for name, want := range map[string]fs.FileMode{"d1": 0o750 | 0o700, "d1/hugo": 0o755, "d1/LICENSE": 0o600, "LICENSE": 0o644} {
+		fi, err := os.Lstat(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != want {
+			t.Errorf("%s: mode: want %v got %v", name, want, fi.Mode().Perm())
+		}
+		if !fi.ModTime().Equal(mtime) {
+			t.Errorf("%s: mtime: want %v got %v", name, mtime, fi.ModTime())
+		}
+	}
+
+	// A symbolic link must not refer to a path outside of the destination.
+	cpio.Reset()
+	writeCpioNewc(t, &cpio, "./hugo", 0o120777, "../outside")
+	writeCpioNewc(t, &cpio, "TRAILER!!!", 0, "")
+	writePkg(t, pkgPath, gzipBytes(t, cpio.Bytes()))
+	if err := extractPkg(pkgPath, t.TempDir(), nil, NewLimiter(DefaultLimits, 0)); err == nil {
+		t.Fatal("expected error for malicious link")
+	}
+}
+
+func TestExtractPkg_ZipSlip(t *testing.T) {
+	t.Parallel()
+
+	var cpio bytes.Buffer
+	writeCpioNewc(t, &cpio, "../evil.txt", 0o100644, "oops")
+	writeCpioNewc(t, &cpio, "TRAILER!!!", 0, "")
+
+	pkgPath := filepath.Join(t.TempDir(), "slip.pkg")
+	writePkg(t, pkgPath, gzipBytes(t, cpio.Bytes()))
+
+	if err := extractPkg(pkgPath, t.TempDir(), nil, NewLimiter(DefaultLimits, 0)); err == nil {
+		t.Fatal("expected zip slip error for pkg")
+	}
+}
+
+func TestExtractPkg_SizeLimit(t *testing.T) {
+	t.Parallel()
+
+	// The header claims an entry larger than the size limit.
+	var cpio bytes.Buffer
+	fmt.Fprintf(&cpio, "070701%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X",
+		0, 0o100644, 0, 0, 1, 0, DefaultLimits.MaxEntryBytes+1, 0, 0, 0, 0, len("big")+1, 0)
+	cpio.WriteString("big\x00\x00\x00")
+
+	pkgPath := filepath.Join(t.TempDir(), "big.pkg")
+	writePkg(t, pkgPath, gzipBytes(t, cpio.Bytes()))
+
+	err := extractPkg(pkgPath, t.TempDir(), nil, NewLimiter(DefaultLimits, 0))
+	if err == nil || !strings.Contains(err.Error(), "size limit") {
+		t.Fatalf("expected size limit error for pkg, got %v", err)
+	}
+}
+
+func TestExtractPkg_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	notXar := filepath.Join(dir, "not-xar.pkg")
+	if err := os.WriteFile(notXar, []byte("not a xar archive, just text"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	truncated := filepath.Join(dir, "truncated.pkg")
+	if err := os.WriteFile(truncated, []byte("not a cpio archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writePkg(t, truncated, gzipBytes(t, []byte("not a cpio archive")))
+
+	for _, name := range []string{notXar, truncated} {
+		if err := extractPkg(name, t.TempDir(), nil, NewLimiter(DefaultLimits, 0)); err == nil {
+			t.Errorf("extractPkg(%s): expected error", filepath.Base(name))
+		}
+	}
+}
+
+// writePkg writes a xar archive to name containing a Payload file with the
+// given content, stored without additional encoding.
+func writePkg(t *testing.T, name string, payload []byte) {
+	t.Helper()
+
+	toc := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<xar><toc><file id="1"><data><length>%d</length><offset>0</offset><size>%d</size><encoding style="application/octet-stream"/></data><type>file</type><name>Payload</name></file></toc></xar>`, len(payload), len(payload))
+
+	var ztoc bytes.Buffer
+	zw := zlib.NewWriter(&ztoc)
+	if _, err := zw.Write([]byte(toc)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	h := xarHeader{
+		HeaderSize:      28,
+		Version:         1,
+		TOCLengthZipped: uint64(ztoc.Len()),
+		TOCLengthUnzip:  uint64(len(toc)),
+	}
+	copy(h.Magic[:], xarMagic)
+	if err := binary.Write(&buf, binary.BigEndian, h); err != nil {
+		t.Fatal(err)
+	}
+	buf.Write(ztoc.Bytes())
+	buf.Write(payload)
+
+	if err := os.WriteFile(name, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeCpioNewc writes a newc cpio entry to w.
+func writeCpioNewc(t *testing.T, w *bytes.Buffer, name string, mode int, content string) {
+	t.Helper()
+	writeCpioNewcAt(t, w, name, mode, content, time.Unix(0, 0))
+}
+
+// writeCpioNewcAt writes a newc cpio entry to w with modification time mtime.
+func writeCpioNewcAt(t *testing.T, w *bytes.Buffer, name string, mode int, content string, mtime time.Time) {
+	t.Helper()
+
+	fmt.Fprintf(w, "070701%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X",
+		0, mode, 0, 0, 1, mtime.Unix(), len(content), 0, 0, 0, 0, len(name)+1, 0)
+	w.WriteString(name + "\x00")
+	w.Write(make([]byte, pad4(int64(110+len(name)+1))))
+	w.WriteString(content)
+	w.Write(make([]byte, pad4(int64(len(content)))))
+}
+
+// gzipBytes returns data compressed with gzip.
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractDeb(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"./usr/local/bin/hugo":           "hugo binary\n",
+		"./usr/share/doc/hugo/copyright": "license\n",
+	}
+
+	tests := []struct {
+		name     string
+		dataName string
+		compress func(t *testing.T, data []byte) []byte
+	}{
+		{"gzip", "data.tar.gz", gzipBytes},
+		{"xz", "data.tar.xz", xzBytes},
+		{"zstd", "data.tar.zst", zstdBytes},
+		{"uncompressed", "data.tar", func(t *testing.T, data []byte) []byte { return data }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			debPath := filepath.Join(t.TempDir(), "hugo.deb")
+			writeDeb(t, debPath, tt.dataName, tt.compress(t, tarBytes(t, files)))
+
+			dstDir := t.TempDir()
+			if err := Extract(debPath, dstDir, true, nil); err != nil {
+				t.Fatalf("Extract error: %v", err)
+			}
+
+			got, err := os.ReadFile(filepath.Join(dstDir, "usr", "local", "bin", "hugo"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "hugo binary\n" {
+				t.Fatalf("hugo: want %q got %q", "hugo binary\n", got)
+			}
+			if _, err := os.Stat(filepath.Join(dstDir, "control")); err == nil {
+				t.Fatal("control archive should not be extracted")
+			}
+			if _, err := os.Stat(debPath); !os.IsNotExist(err) {
+				t.Fatal("source archive should be removed")
+			}
+		})
+	}
+}
+
+func TestExtractDeb_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	notAr := filepath.Join(dir, "not-ar.deb")
+	if err := os.WriteFile(notAr, []byte("not an ar archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	noData := filepath.Join(dir, "no-data.deb")
+	writeDeb(t, noData, "other.tar.gz", gzipBytes(t, tarBytes(t, map[string]string{"a": "a"})))
+
+	badCompression := filepath.Join(dir, "bad-compression.deb")
+	writeDeb(t, badCompression, "data.tar.lz", []byte("data"))
+
+	zipSlip := filepath.Join(dir, "zip-slip.deb")
+	writeDeb(t, zipSlip, "data.tar.gz", gzipBytes(t, tarBytes(t, map[string]string{"../evil.txt": "oops"})))
+
+	for _, name := range []string{notAr, noData, badCompression, zipSlip} {
+		if err := extractDeb(name, t.TempDir(), nil, NewLimiter(DefaultLimits, 0)); err == nil {
+			t.Errorf("extractDeb(%s): expected error", filepath.Base(name))
+		}
+	}
+}
+
+// writeDeb writes a Debian package to name with a control archive and a data
+// archive named dataName with the given content.
+func writeDeb(t *testing.T, name, dataName string, data []byte) {
+	t.Helper()
+
+	members := []struct {
+		name    string
+		content []byte
+	}{
+		{"debian-binary", []byte("2.0\n")},
+		{"control.tar.gz", gzipBytes(t, tarBytes(t, map[string]string{"./control": "Package: hugo\n"}))},
+		{dataName, data},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(arMagic)
+	for _, m := range members {
+		fmt.Fprintf(&buf, "%-16s%-12d%-6d%-6d%-8o%-10d`\n", m.name+"/", 0, 0, 0, 0o644, len(m.content))
+		buf.Write(m.content)
+		if len(m.content)%2 == 1 {
+			buf.WriteByte('\n')
+		}
+	}
+
+	if err := os.WriteFile(name, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tarBytes returns a tarball containing files, keyed by name.
+func tarBytes(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		content := files[name]
+		hdr := &tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// xzBytes returns data compressed with xz.
+func xzBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	xw, err := xz.NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := xw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := xw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// zstdBytes returns data compressed with zstd.
+func zstdBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw, err := zstd.NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tarball := tarBytes(t, map[string]string{"hugo": "hugo binary\n"})
+
+	srcDir := t.TempDir()
+	if err := helpers.CopyDirectoryContent("testdata", srcDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// File names deliberately omit or misstate the extension.
+	files := map[string][]byte{
+		"tarball.bin":   tarball,
+		"gz.bin":        gzipBytes(t, tarball),
+		"xz.zip":        xzBytes(t, tarball),
+		"zst.tar.gz":    zstdBytes(t, tarball),
+		"unknown.tar":   []byte("plain text"),
+		"empty.tar.gz":  nil,
+		"ar-not-deb.ar": []byte(arMagic + "libfoo.o/       "),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeDeb(t, filepath.Join(dir, "deb.bin"), "data.tar.gz", gzipBytes(t, tarball))
+	writePkg(t, filepath.Join(dir, "pkg.bin"), gzipBytes(t, nil))
+	if err := os.Rename(filepath.Join(srcDir, "test.zip"), filepath.Join(dir, "zip.bin")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want string // empty if the format is unknown
+	}{
+		{"tarball.bin", "tar"},
+		{"gz.bin", "tar.gz"},
+		{"xz.zip", "tar.xz"},
+		{"zst.tar.gz", "tar.zst"},
+		{"deb.bin", "deb"},
+		{"pkg.bin", "pkg"},
+		{"zip.bin", "zip"},
+		{"unknown.tar", ""},
+		{"empty.tar.gz", ""},
+		{"ar-not-deb.ar", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Detect(filepath.Join(dir, tt.name))
+			if tt.want == "" {
+				if err == nil || err.Error() != "unknown archive format" {
+					t.Fatalf("Detect: want unknown archive format error, got %v, %v", e, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Detect error: %v", err)
+			}
+			if e.Name() != tt.want {
+				t.Fatalf("Detect: want %s got %s", tt.want, e.Name())
+			}
+		})
+	}
+}
+
+func TestExtract_CompressedTar(t *testing.T) {
+	t.Parallel()
+
+	tarball := tarBytes(t, map[string]string{"d1/hugo": "hugo binary\n"})
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"hugo.tar.xz", xzBytes(t, tarball)},
+		{"hugo.tar.zst", zstdBytes(t, tarball)},
+		{"hugo.tar", tarball},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src := filepath.Join(t.TempDir(), tt.name)
+			if err := os.WriteFile(src, tt.content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			dst := t.TempDir()
+			if err := Extract(src, dst, false, nil); err != nil {
+				t.Fatalf("Extract error: %v", err)
+			}
+			got, err := os.ReadFile(filepath.Join(dst, "d1", "hugo"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "hugo binary\n" {
+				t.Fatalf("hugo: want %q got %q", "hugo binary\n", got)
+			}
+		})
+	}
+}
+
+// testExtractor is an Extractor for a text format in which each line is the
+// name of an empty file.
+type testExtractor struct{}
+
+func (testExtractor) Name() string { return "test" }
+
+func (testExtractor) Detect(header []byte) bool {
+	return bytes.HasPrefix(header, []byte("#test-archive\n"))
+}
+
+func (testExtractor) Extract(src, dst string, filter Filter, l *Limiter) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Fields(string(data))[1:] {
+		if err := l.Entry(); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dst, name), nil, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	Register(testExtractor{})
+
+	if got := Extractors(); got[len(got)-1].Name() != "test" {
+		t.Fatalf("Extractors: want test last, got %s", got[len(got)-1].Name())
+	}
+
+	src := filepath.Join(t.TempDir(), "files.txt")
+	if err := os.WriteFile(src, []byte("#test-archive\na.txt\nb.txt\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dst := t.TempDir()
+	if err := Extract(src, dst, true, nil); err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if _, err := os.Stat(filepath.Join(dst, name)); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatal("source archive should be removed")
+	}
+}
+
+// tarEntry is an entry written by writeTarEntries.
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+	mode     int64
+	mtime    time.Time
+}
+
+// writeTarEntries writes a gzipped tarball containing entries to name.
+func writeTarEntries(t *testing.T, name string, entries []tarEntry) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		mode := e.mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		hdr := &tar.Header{
+			Name:     e.name,
+			Typeflag: e.typeflag,
+			Linkname: e.linkname,
+			Mode:     mode,
+			Size:     int64(len(e.content)),
+			ModTime:  e.mtime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, gzipBytes(t, buf.Bytes()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractTar_Links(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links requires elevated privileges on Windows")
+	}
+	t.Parallel()
+
+	mtime := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	src := filepath.Join(t.TempDir(), "links.tar.gz")
+	writeTarEntries(t, src, []tarEntry{
+		{name: "d1/", typeflag: tar.TypeDir, mode: 0o750, mtime: mtime},
+		{name: "d1/hugo", typeflag: tar.TypeReg, content: "hugo binary\n", mode: 0o755, mtime: mtime},
+		{name: "d1/LICENSE", typeflag: tar.TypeReg, content: "license\n", mode: 0o600, mtime: mtime},
+		{name: "hugo", typeflag: tar.TypeSymlink, linkname: "d1/hugo"},
+		{name: "d1/self", typeflag: tar.TypeSymlink, linkname: "../d1/./hugo"},
+		{name: "LICENSE", typeflag: tar.TypeLink, linkname: "d1/LICENSE"},
+	})
+
+	dst := t.TempDir()
+	if err := Extract(src, dst, false, nil); err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+
+	for name, want := range map[string]string{"hugo": "d1/hugo", "d1/self": "hugo"} {
+		got, err := os.Readlink(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != filepath.FromSlash(want) {
+			t.Errorf("%s: link target: want %q got %q", name, want, got)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dst, "LICENSE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "license\n" {
+		t.Errorf("LICENSE: want %q got %q", "license\n", data)
+	}
+
+	for name, want := range map[string]fs.FileMode{"d1": 0o750 | 0o700, "d1/hugo": 0o755, "d1/LICENSE": 0o600} {
+		fi, err := os.Stat(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != want {
+			t.Errorf("%s: mode: want %v got %v", name, want, fi.Mode().Perm())
+		}
+		if !fi.ModTime().Equal(mtime) {
+			t.Errorf("%s: mtime: want %v got %v", name, mtime, fi.ModTime())
+		}
+	}
+}
+
+func TestExtractTar_MaliciousLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links requires elevated privileges on Windows")
+	}
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{"symlinkAbsolute", []tarEntry{
+			{name: "hugo", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"},
+		}},
+		{"symlinkParent", []tarEntry{
+			{name: "hugo", typeflag: tar.TypeSymlink, linkname: "../outside"},
+		}},
+		{"symlinkNestedParent", []tarEntry{
+			{name: "d1/d2/hugo", typeflag: tar.TypeSymlink, linkname: "../../../outside"},
+		}},
+		{"symlinkThroughLink", []tarEntry{
+			{name: "self", typeflag: tar.TypeSymlink, linkname: "."},
+			{name: "escape", typeflag: tar.TypeSymlink, linkname: "self/../.."},
+		}},
+		{"fileThroughSymlink", []tarEntry{
+			{name: "d1/", typeflag: tar.TypeDir},
+			{name: "link", typeflag: tar.TypeSymlink, linkname: "d1"},
+			{name: "link/evil", typeflag: tar.TypeReg, content: "oops"},
+		}},
+		{"hardlinkParent", []tarEntry{
+			{name: "hugo", typeflag: tar.TypeLink, linkname: "../outside"},
+		}},
+		{"hardlinkAbsolute", []tarEntry{
+			{name: "hugo", typeflag: tar.TypeLink, linkname: "/etc/passwd"},
+		}},
+		{"hardlinkToSymlink", []tarEntry{
+			{name: "self", typeflag: tar.TypeSymlink, linkname: "."},
+			{name: "hugo", typeflag: tar.TypeLink, linkname: "self"},
+		}},
+		{"hardlinkToMissing", []tarEntry{
+			{name: "hugo", typeflag: tar.TypeLink, linkname: "missing"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			outside := filepath.Join(root, "outside")
+			if err := os.WriteFile(outside, []byte("outside\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			dst := filepath.Join(root, "dst")
+			if err := os.Mkdir(dst, 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			src := filepath.Join(t.TempDir(), "evil.tar.gz")
+			writeTarEntries(t, src, tt.entries)
+
+			if err := Extract(src, dst, false, nil); err == nil {
+				t.Fatal("expected error for malicious archive")
+			}
+			if _, err := os.Stat(filepath.Join(dst, "d1", "evil")); err == nil {
+				t.Fatal("file written through link")
+			}
+			data, err := os.ReadFile(outside)
+			if err != nil || string(data) != "outside\n" {
+				t.Fatalf("file outside of destination modified: %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestExtractTar_ReplaceSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links requires elevated privileges on Windows")
+	}
+	t.Parallel()
+
+	// A regular file that follows a symbolic link of the same name replaces
+	// the link instead of writing to its target.
+	src := filepath.Join(t.TempDir(), "replace.tar.gz")
+	writeTarEntries(t, src, []tarEntry{
+		{name: "LICENSE", typeflag: tar.TypeReg, content: "license\n"},
+		{name: "hugo", typeflag: tar.TypeSymlink, linkname: "LICENSE"},
+		{name: "hugo", typeflag: tar.TypeReg, content: "hugo binary\n", mode: 0o755},
+	})
+
+	dst := t.TempDir()
+	if err := Extract(src, dst, false, nil); err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+
+	for name, want := range map[string]string{"LICENSE": "license\n", "hugo": "hugo binary\n"} {
+		data, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s: want %q got %q", name, want, data)
+		}
+	}
+}
+
+func TestExtractZip_Links(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links requires elevated privileges on Windows")
+	}
+	t.Parallel()
+
+	mtime := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	writeZipEntries := func(name string, links map[string]string) {
+		t.Helper()
+
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		fh := &zip.FileHeader{Name: "d1/hugo", Method: zip.Deflate, Modified: mtime}
+		fh.SetMode(0o755)
+		w, err := zw.CreateHeader(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, "hugo binary\n")
+		for _, link := range slices.Sorted(maps.Keys(links)) {
+			fh := &zip.FileHeader{Name: link, Method: zip.Store}
+			fh.SetMode(fs.ModeSymlink | 0o777)
+			w, err := zw.CreateHeader(fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			io.WriteString(w, links[link])
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Valid link
+	src := filepath.Join(t.TempDir(), "links.zip")
+	writeZipEntries(src, map[string]string{"hugo": "d1/hugo"})
+	dst := t.TempDir()
+	if err := Extract(src, dst, false, nil); err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "hugo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hugo binary\n" {
+		t.Errorf("hugo: want %q got %q", "hugo binary\n", data)
+	}
+	fi, err := os.Stat(filepath.Join(dst, "d1", "hugo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o755 || !fi.ModTime().Equal(mtime) {
+		t.Errorf("d1/hugo: want mode 0755 and mtime %v, got %v and %v", mtime, fi.Mode().Perm(), fi.ModTime())
+	}
+
+	// Malicious links
+	for _, target := range []string{"../outside", "/etc/passwd", "d1/../../outside"} {
+		src := filepath.Join(t.TempDir(), "evil.zip")
+		writeZipEntries(src, map[string]string{"hugo": target})
+		if err := Extract(src, t.TempDir(), false, nil); err == nil {
+			t.Errorf("%s: expected error for malicious link", target)
+		}
+	}
+}
+
+func TestExtractWithLimits(t *testing.T) {
+	t.Parallel()
+
+	zeros := strings.Repeat("\x00", 2<<20)
+
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		limits  Limits
+		want    Limit
+		wantErr bool
+	}{
+		{
+			name:    "entrySize",
+			entries: []tarEntry{{name: "a", typeflag: tar.TypeReg, content: "0123456789"}},
+			limits:  Limits{MaxEntryBytes: 9},
+			want:    LimitEntryBytes,
+			wantErr: true,
+		},
+		{
+			name: "totalSize",
+			entries: []tarEntry{
+				{name: "a", typeflag: tar.TypeReg, content: "0123456789"},
+				{name: "b", typeflag: tar.TypeReg, content: "0123456789"},
+			},
+			limits:  Limits{MaxEntryBytes: 10, MaxTotalBytes: 15},
+			want:    LimitTotalBytes,
+			wantErr: true,
+		},
+		{
+			name: "entries",
+			entries: []tarEntry{
+				{name: "d1/", typeflag: tar.TypeDir},
+				{name: "d1/a", typeflag: tar.TypeReg},
+				{name: "d1/b", typeflag: tar.TypeReg},
+			},
+			limits:  Limits{MaxEntries: 2},
+			want:    LimitEntries,
+			wantErr: true,
+		},
+		{
+			name:    "ratio",
+			entries: []tarEntry{{name: "zeros", typeflag: tar.TypeReg, content: zeros}},
+			limits:  Limits{MaxRatio: 100},
+			want:    LimitRatio,
+			wantErr: true,
+		},
+		{
+			name:    "withinLimits",
+			entries: []tarEntry{{name: "zeros", typeflag: tar.TypeReg, content: zeros}},
+			limits:  Limits{MaxEntryBytes: 2 << 20, MaxTotalBytes: 2 << 20, MaxEntries: 1, MaxRatio: 10000},
+		},
+		{
+			name:    "noLimits",
+			entries: []tarEntry{{name: "zeros", typeflag: tar.TypeReg, content: zeros}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src := filepath.Join(t.TempDir(), "limits.tar.gz")
+			writeTarEntries(t, src, tt.entries)
+
+			err := ExtractWithLimits(src, t.TempDir(), false, nil, tt.limits)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ExtractWithLimits error: %v", err)
+				}
+				return
+			}
+			var le *LimitError
+			if !errors.As(err, &le) {
+				t.Fatalf("ExtractWithLimits: want *LimitError, got %v", err)
+			}
+			if le.Limit != tt.want {
+				t.Fatalf("ExtractWithLimits: want limit %d got %d (%v)", tt.want, le.Limit, err)
+			}
+		})
+	}
+}
+
+func TestExtractWithLimits_Zip(t *testing.T) {
+	t.Parallel()
+
+	src := filepath.Join(t.TempDir(), "limits.zip")
+	files := map[string]string{}
+	for i := range 5 {
+		files[fmt.Sprintf("f%d.txt", i)] = "0123456789"
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, files[name])
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for limits, want := range map[Limits]Limit{
+		{MaxEntries: 4}:     LimitEntries,
+		{MaxTotalBytes: 45}: LimitTotalBytes,
+		{MaxEntryBytes: 5}:  LimitEntryBytes,
+	} {
+		err := ExtractWithLimits(src, t.TempDir(), false, nil, limits)
+		var le *LimitError
+		if !errors.As(err, &le) || le.Limit != want {
+			t.Errorf("%+v: want limit %d, got %v", limits, want, err)
+		}
+	}
+}
+
+func TestExtractTarGZReader(t *testing.T) {
+	t.Parallel()
+
+	src := filepath.Join(t.TempDir(), "stream.tar.gz")
+	writeTarEntries(t, src, []tarEntry{
+		{name: "d1/", typeflag: tar.TypeDir},
+		{name: "d1/hugo", typeflag: tar.TypeReg, content: "hugo"},
+		{name: "zeros", typeflag: tar.TypeReg, content: strings.Repeat("\x00", 2<<20)},
+	})
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ExtractTarGZReader(bytes.NewReader(data), t.TempDir(), nil, DefaultLimits)
+	var le *LimitError
+	if !errors.As(err, &le) || le.Limit != LimitRatio {
+		t.Fatalf("ExtractTarGZReader: want ratio limit error, got %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := ExtractTarGZReader(bytes.NewReader(data), dst, nil, Limits{}); err != nil {
+		t.Fatalf("ExtractTarGZReader error: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dst, "d1", "hugo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hugo" {
+		t.Fatalf("ExtractTarGZReader: want %q got %q", "hugo", got)
+	}
+
+	if err := ExtractTarGZReader(strings.NewReader("not gzip"), t.TempDir(), nil, DefaultLimits); err == nil {
+		t.Fatal("ExtractTarGZReader: want error for invalid gzip stream")
+	}
+}
+
+func TestExecutableFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		licenses bool
+		want     bool
+	}{
+		{"hugo", false, true},
+		{"usr/local/bin/hugo", false, true},
+		{"hugo.exe", false, false},
+		{"hugo-extended", false, false},
+		{"LICENSE", false, false},
+		{"LICENSE", true, true},
+		{"sub/License.md", true, true},
+		{"COPYING", true, true},
+		{"NOTICE.txt", true, true},
+		{"README.md", true, false},
+		{"sub", true, false},
+	}
+	for _, tt := range tests {
+		if got := ExecutableFilter("hugo", tt.licenses)(tt.name); got != tt.want {
+			t.Errorf("ExecutableFilter(hugo, %t)(%q): want %t got %t", tt.licenses, tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestExtract_Filter(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"./hugo":           "hugo binary\n",
+		"./LICENSE":        "license\n",
+		"./README.md":      "readme\n",
+		"./docs/hugo.html": "docs\n",
+	}
+
+	tgz := filepath.Join(t.TempDir(), "hugo.tar.gz")
+	if err := os.WriteFile(tgz, gzipBytes(t, tarBytes(t, files)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	zipPath := filepath.Join(t.TempDir(), "hugo.zip")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		w, err := zw.Create(strings.TrimPrefix(name, "./"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, files[name])
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(zipPath, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cpio bytes.Buffer
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		writeCpioNewc(t, &cpio, name, 0o100755, files[name])
+	}
+	writeCpioNewc(t, &cpio, "TRAILER!!!", 0, "")
+	pkgPath := filepath.Join(t.TempDir(), "hugo.pkg")
+	writePkg(t, pkgPath, gzipBytes(t, cpio.Bytes()))
+
+	tests := []struct {
+		licenses bool
+		want     []string
+	}{
+		{false, []string{"hugo"}},
+		{true, []string{"LICENSE", "hugo"}},
+	}
+	for _, src := range []string{tgz, zipPath, pkgPath} {
+		for _, tt := range tests {
+			dst := t.TempDir()
+			if err := Extract(src, dst, false, ExecutableFilter("hugo", tt.licenses)); err != nil {
+				t.Fatalf("Extract(%s) error: %v", filepath.Base(src), err)
+			}
+			var got []string
+			err := filepath.WalkDir(dst, func(p string, d fs.DirEntry, err error) error {
+				if err != nil || p == dst {
+					return err
+				}
+				rel, _ := filepath.Rel(dst, p)
+				got = append(got, filepath.ToSlash(rel))
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Extract(%s) with licenses %t: want %v got %v", filepath.Base(src), tt.licenses, tt.want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// decompress returns a reader for the content of r, compressed with the
+// method identified by the file extension ext (.gz, .xz, .zst, or empty for
+// none), and a function to release its resources.
+func decompress(ext string, r io.Reader) (io.Reader, func(), error) {
+	switch ext {
+	case "":
+		return r, func() {}, nil
+	case ".gz":
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gzr, func() { gzr.Close() }, nil
+	case ".xz":
+		xzr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return xzr, func() {}, nil
+	case ".zst":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, zr.Close, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported compression %q", ext)
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cpio magic numbers for the portable ASCII (odc) and new ASCII (newc and crc)
+// formats.
+const (
+	cpioMagicODC  = "070707"
+	cpioMagicNewc = "070701"
+	cpioMagicCRC  = "070702"
+)
+
+// cpioTrailer is the name of the entry that marks the end of a cpio archive.
+const cpioTrailer = "TRAILER!!!"
+
+// maxCpioNameBytes is the maximum length of an entry name in a cpio archive.
+const maxCpioNameBytes = 4096
+
+// cpio file type bits of the mode field.
+const (
+	cpioTypeMask    = 0o170000
+	cpioTypeDir     = 0o040000
+	cpioTypeReg     = 0o100000
+	cpioTypeSymlink = 0o120000
+)
+
+// cpioHeader is the decoded header of a cpio archive entry.
+type cpioHeader struct {
+	Name    string
+	Mode    int64
+	Size    int64
+	ModTime time.Time
+}
+
+// cpioReader reads the entries of a cpio archive in the odc, newc, or crc
+// format.
+type cpioReader struct {
+	r       *bufio.Reader
+	pad     int64 // bytes of padding after the current entry's data
+	remain  int64 // unread bytes of the current entry's data
+	aligned bool  // whether the current entry uses 4-byte alignment
+}
+
+// newCpioReader returns a cpioReader reading from r.
+func newCpioReader(r io.Reader) *cpioReader {
+	return &cpioReader{r: bufio.NewReader(r)}
+}
+
+// next advances to the next entry, returning io.EOF at the end of the archive.
+// An archive that ends before its trailer entry is reported as
+// io.ErrUnexpectedEOF.
+func (cr *cpioReader) next() (*cpioHeader, error) {
+	h, err := cr.readHeader()
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if h.Name == cpioTrailer {
+		return nil, io.EOF
+	}
+	return h, nil
+}
+
+// readHeader skips the rest of the current entry and reads the next header.
+func (cr *cpioReader) readHeader() (*cpioHeader, error) {
+	if _, err := io.CopyN(io.Discard, cr.r, cr.remain+cr.pad); err != nil {
+		return nil, err
+	}
+	cr.remain, cr.pad = 0, 0
+
+	magic := make([]byte, 6)
+	if _, err := io.ReadFull(cr.r, magic); err != nil {
+		return nil, err
+	}
+
+	var (
+		h        cpioHeader
+		nameSize int64
+		hdrSize  int64
+		err      error
+	)
+	switch string(magic) {
+	case cpioMagicODC:
+		// dev, ino, mode, uid, gid, nlink, rdev, mtime, namesize, filesize
+		fields, ferr := readCpioFields(cr.r, []int{6, 6, 6, 6, 6, 6, 6, 11, 6, 11}, 8)
+		if ferr != nil {
+			return nil, ferr
+		}
+		h.Mode, nameSize, h.Size = fields[2], fields[8], fields[9]
+		h.ModTime = time.Unix(fields[7], 0)
+		hdrSize = 76
+		cr.aligned = false
+	case cpioMagicNewc, cpioMagicCRC:
+		// ino, mode, uid, gid, nlink, mtime, filesize, devmajor, devminor,
+		// rdevmajor, rdevminor, namesize, check
+		fields, ferr := readCpioFields(cr.r, []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8}, 16)
+		if ferr != nil {
+			return nil, ferr
+		}
+		h.Mode, h.Size, nameSize = fields[1], fields[6], fields[11]
+		h.ModTime = time.Unix(fields[5], 0)
+		hdrSize = 110
+		cr.aligned = true
+	default:
+		return nil, fmt.Errorf("invalid cpio header")
+	}
+
+	if nameSize < 1 || nameSize > maxCpioNameBytes || h.Size < 0 {
+		return nil, fmt.Errorf("invalid cpio header")
+	}
+	name := make([]byte, nameSize)
+	if _, err = io.ReadFull(cr.r, name); err != nil {
+		return nil, err
+	}
+	h.Name = strings.TrimRight(string(name), "\x00")
+
+	if cr.aligned {
+		if _, err := io.CopyN(io.Discard, cr.r, pad4(hdrSize+nameSize)); err != nil {
+			return nil, err
+		}
+		cr.pad = pad4(h.Size)
+	}
+	cr.remain = h.Size
+	return &h, nil
+}
+
+// Read reads from the current entry's data.
+func (cr *cpioReader) Read(p []byte) (int, error) {
+	if cr.remain <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > cr.remain {
+		p = p[:cr.remain]
+	}
+	n, err := cr.r.Read(p)
+	cr.remain -= int64(n)
+	if err == io.EOF && cr.remain > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// readCpioFields reads fixed-width numeric fields in the given base.
+func readCpioFields(r io.Reader, widths []int, base int) ([]int64, error) {
+	fields := make([]int64, len(widths))
+	for i, w := range widths {
+		buf := make([]byte, w)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		v, err := strconv.ParseInt(string(buf), base, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpio header: %w", err)
+		}
+		fields[i] = v
+	}
+	return fields, nil
+}
+
+// pad4 returns the number of bytes needed to align n to a multiple of 4.
+func pad4(n int64) int64 {
+	return (4 - n%4) % 4
+}
+
+// extractCpio extracts the entries selected by filter of the cpio archive
+// read from r to the dst directory, preserving the permission bits and
+// modification times of files and directories. Symbolic links are extracted
+// as described in extractSymlink. Hard links are not supported, because cpio
+// records them as entries that share an inode rather than by name; each such
+// entry that has content is extracted as a regular file.
+func extractCpio(r io.Reader, dst string, filter Filter, l *Limiter) error {
+	cr := newCpioReader(r)
+	dirs := dirMetadata{}
+
+	for {
+		h, err := cr.next()
+		switch {
+		case err == io.EOF:
+			return dirs.apply()
+		case err != nil:
+			return err
+		}
+
+		if err := l.Entry(); err != nil {
+			return err
+		}
+
+		name := strings.TrimPrefix(h.Name, "./")
+		if name == "." || name == "" {
+			continue
+		}
+
+		target, err := safePath(dst, name)
+		if err != nil {
+			return err
+		}
+		if !filter.selects(name) {
+			continue
+		}
+
+		perm := os.FileMode(h.Mode & 0o777)
+		switch h.Mode & cpioTypeMask {
+		case cpioTypeDir:
+			if _, err := os.Stat(target); err != nil {
+				if err := os.MkdirAll(target, 0o755); err != nil {
+					return err
+				}
+			}
+			dirs.set(target, perm, h.ModTime)
+		case cpioTypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			err = copyFileFromCpio(target, h, cr, l)
+			if err != nil {
+				return err
+			}
+			err = setFileMetadata(target, perm, h.ModTime)
+			if err != nil {
+				return err
+			}
+		case cpioTypeSymlink:
+			linkname, err := readCpioLink(h, cr)
+			if err != nil {
+				return err
+			}
+			err = extractSymlink(dst, target, linkname)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readCpioLink returns the target of the symbolic link that is the current
+// entry of a cpio archive.
+func readCpioLink(h *cpioHeader, cr *cpioReader) (string, error) {
+	if h.Size > maxLinkBytes {
+		return "", fmt.Errorf("archive link %s exceeds size limit of %d bytes", h.Name, maxLinkBytes)
+	}
+	data, err := io.ReadAll(cr)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// copyFileFromCpio copies the current entry of a cpio archive to dst.
+func copyFileFromCpio(dst string, h *cpioHeader, cr *cpioReader, l *Limiter) (retErr error) {
+	if err := l.checkSize(h.Size); err != nil {
+		return err
+	}
+	if err := removeNonDir(dst); err != nil {
+		return err
+	}
+
+	df, err := os.OpenFile(dst, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(h.Mode&0o777))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := df.Close(); cerr != nil && retErr == nil {
+			retErr = cerr
+		}
+	}()
+
+	_, err = l.Copy(df, cr)
+	return err
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// extractDeb unpacks the contents of a Debian package (src), selected by
+// filter, to the dst directory. It specifically extracts the files contained
+// within the package's data archive, a tarball compressed with gzip, xz, or
+// zstd stored in the package's ar archive. The package's control information
+// is ignored.
+func extractDeb(src, dst string, filter Filter, l *Limiter) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ar, err := newArReader(f)
+	if err != nil {
+		return err
+	}
+
+	for {
+		h, err := ar.next()
+		switch {
+		case err == io.EOF:
+			return fmt.Errorf("deb file does not contain a data archive")
+		case err != nil:
+			return err
+		}
+
+		if !strings.HasPrefix(h.Name, "data.tar") {
+			continue
+		}
+
+		r, closer, err := decompress(strings.TrimPrefix(h.Name, "data.tar"), ar)
+		if err != nil {
+			return fmt.Errorf("invalid deb %s: %w", h.Name, err)
+		}
+		defer closer()
+
+		err = extractTar(r, dst, filter, l)
+		if err != nil {
+			return fmt.Errorf("invalid deb %s: %w", h.Name, err)
+		}
+		return nil
+	}
+}
@@ -17,62 +17,54 @@ limitations under the License.
 package archive
 
 import (
-	"errors"
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"fmt"
-	"io/fs"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime"
-
-	"github.com/jmooring/hvm/helpers"
+	"io"
 )
 
-// extractPkg unpacks the contents of a macOS .pkg file (src) to the dst
-// directory. It specifically extracts the files contained within the
-// package's Payload.
-func extractPkg(src, dst string) error {
-	if runtime.GOOS != "darwin" {
-		return fmt.Errorf("extraction of pkg file is limited to darwin")
-	}
-
-	exists, err := helpers.Exists(src)
+// extractPkg unpacks the contents of a macOS .pkg file (src), selected by
+// filter, to the dst directory. It specifically extracts the files contained
+// within the package's Payload, a gzip-compressed cpio archive stored in the
+// package's xar archive.
+func extractPkg(src, dst string, filter Filter, l *Limiter) error {
+	x, f, err := openXarFile(src)
 	if err != nil {
 		return err
 	}
-	if !exists {
-		return fmt.Errorf("unable to find %s", src)
+	defer f.Close()
+
+	pf, ok := x.find("Payload")
+	if !ok {
+		return fmt.Errorf("pkg file does not contain a Payload")
 	}
 
-	tempDir, err := os.MkdirTemp("", "")
+	rc, err := x.open(pf)
 	if err != nil {
 		return err
 	}
-	defer os.RemoveAll(tempDir)
-
-	expansionDir := filepath.Join(tempDir, "expanded")
+	defer rc.Close()
 
-	cmd := exec.Command("pkgutil", "--expand-full", src, expansionDir)
-	co, err := cmd.CombinedOutput()
+	payload := bufio.NewReader(rc)
+	magic, err := payload.Peek(2)
 	if err != nil {
-		return fmt.Errorf("pkgutil command failed: %w: %s", err, co)
+		return fmt.Errorf("invalid pkg Payload: %w", err)
 	}
 
-	payloadDir := filepath.Join(expansionDir, "Payload")
-	info, err := os.Stat(payloadDir)
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return fmt.Errorf("expanded pkg does not contain a Payload directory at expected location: %s", payloadDir)
+	var r io.Reader = payload
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzr, err := gzip.NewReader(payload)
+		if err != nil {
+			return fmt.Errorf("invalid pkg Payload: %w", err)
 		}
-		return err
-	}
-	if !info.IsDir() {
-		return fmt.Errorf("expected Payload at %s to be a directory", payloadDir)
+		defer gzr.Close()
+		r = gzr
 	}
 
-	err = helpers.CopyDirectoryContent(payloadDir, dst)
+	err = extractCpio(r, dst, filter, l)
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid pkg Payload: %w", err)
 	}
 	return nil
 }
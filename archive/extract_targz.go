@@ -19,48 +19,112 @@ package archive
 import (
 	"archive/tar"
 	"bufio"
-	"compress/gzip"
-	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
 // extractTarGZ extracts a gzipped tarball (src) to the dst directory.
-func extractTarGZ(src, dst string) error {
+func extractTarGZ(src, dst string, filter Filter, l *Limiter) error {
+	return extractCompressedTar(src, dst, ".gz", filter, l)
+}
+
+// extractTarXZ extracts an xz-compressed tarball (src) to the dst directory.
+func extractTarXZ(src, dst string, filter Filter, l *Limiter) error {
+	return extractCompressedTar(src, dst, ".xz", filter, l)
+}
+
+// extractTarZst extracts a zstd-compressed tarball (src) to the dst directory.
+func extractTarZst(src, dst string, filter Filter, l *Limiter) error {
+	return extractCompressedTar(src, dst, ".zst", filter, l)
+}
+
+// extractTarFile extracts an uncompressed tarball (src) to the dst directory.
+func extractTarFile(src, dst string, filter Filter, l *Limiter) error {
+	return extractCompressedTar(src, dst, "", filter, l)
+}
+
+// ExtractTarGZReader extracts the entries selected by filter of a gzipped
+// tarball read from r to the dst directory, returning a *LimitError if
+// extracting it exceeds limits. It allows an archive to be extracted as it is
+// downloaded, without first writing it to disk. Because the size of the
+// archive is not known in advance, the compression ratio limit is enforced
+// against the number of bytes read from r so far. Reading stops at the end of
+// the tarball; the caller is responsible for consuming any remaining content
+// of r.
+func ExtractTarGZReader(r io.Reader, dst string, filter Filter, limits Limits) error {
+	l := NewLimiter(limits, 0)
+
+	gzr, closer, err := decompress(".gz", &countingReader{r: r, n: &l.archiveSize})
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	return extractTar(gzr, dst, filter, l)
+}
+
+// A countingReader adds the number of bytes read from r to *n.
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+// Read implements io.Reader.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	*c.n += int64(n)
+	return n, err
+}
+
+// extractCompressedTar extracts a tarball (src), compressed with the method
+// identified by the file extension ext, to the dst directory.
+func extractCompressedTar(src, dst, ext string, filter Filter, l *Limiter) error {
 	f, err := os.Open(src)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
-	r := bufio.NewReader(f)
-
-	gzr, err := gzip.NewReader(r)
+	r, closer, err := decompress(ext, bufio.NewReader(f))
 	if err != nil {
 		return err
 	}
-	defer gzr.Close()
+	defer closer()
+
+	return extractTar(r, dst, filter, l)
+}
 
-	tr := tar.NewReader(gzr)
+// extractTar extracts the entries selected by filter of the tarball read from r
+// to the dst directory. Symbolic links and hard links are extracted if their
+// targets are within dst. Permission bits and modification times are
+// preserved.
+func extractTar(r io.Reader, dst string, filter Filter, l *Limiter) error {
+	tr := tar.NewReader(r)
+	dirs := dirMetadata{}
 
 	for {
 		th, err := tr.Next()
 
 		switch {
 		case err == io.EOF:
-			return nil
+			return dirs.apply()
 		case err != nil:
 			return err
 		case th == nil:
 			continue
 		}
 
-		target := filepath.Join(dst, th.Name)
-		rel, relErr := filepath.Rel(dst, target)
-		if relErr != nil || strings.HasPrefix(rel, "..") {
-			return fmt.Errorf("detected unsafe file in archive (zip slip)")
+		if err := l.Entry(); err != nil {
+			return err
+		}
+
+		target, err := safePath(dst, th.Name)
+		if err != nil {
+			return err
+		}
+		if !filter.selects(th.Name) {
+			continue
 		}
 
 		switch th.Typeflag {
@@ -70,8 +134,26 @@ func extractTarGZ(src, dst string) error {
 					return err
 				}
 			}
+			dirs.set(target, th.FileInfo().Mode().Perm(), th.ModTime)
 		case tar.TypeReg:
-			err = copyFileFromTarGZ(target, th, tr)
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			err = copyFileFromTarGZ(target, th, tr, l)
+			if err != nil {
+				return err
+			}
+			err = setFileMetadata(target, th.FileInfo().Mode().Perm(), th.ModTime)
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			err = extractSymlink(dst, target, th.Linkname)
+			if err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			err = extractHardlink(dst, target, th.Linkname, l)
 			if err != nil {
 				return err
 			}
@@ -80,8 +162,15 @@ func extractTarGZ(src, dst string) error {
 }
 
 // copyFileFromTarGZ copies a file within a tar.gz archive to the target path.
-func copyFileFromTarGZ(dst string, th *tar.Header, tr *tar.Reader) (retErr error) {
-	df, err := os.OpenFile(dst, os.O_CREATE|os.O_RDWR, os.FileMode(th.Mode))
+func copyFileFromTarGZ(dst string, th *tar.Header, tr *tar.Reader, l *Limiter) (retErr error) {
+	if err := l.checkSize(th.Size); err != nil {
+		return err
+	}
+	if err := removeNonDir(dst); err != nil {
+		return err
+	}
+
+	df, err := os.OpenFile(dst, os.O_CREATE|os.O_RDWR|os.O_TRUNC, th.FileInfo().Mode().Perm())
 	if err != nil {
 		return err
 	}
@@ -91,13 +180,6 @@ func copyFileFromTarGZ(dst string, th *tar.Header, tr *tar.Reader) (retErr error
 		}
 	}()
 
-	lr := &io.LimitedReader{R: tr, N: maxExtractBytes + 1}
-	if _, err := io.Copy(df, lr); err != nil {
-		return err
-	}
-	if lr.N == 0 {
-		return fmt.Errorf("archive entry exceeds size limit of %d bytes", maxExtractBytes)
-	}
-
-	return nil
+	_, err = l.Copy(df, tr)
+	return err
 }
@@ -20,57 +20,108 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
-// extractZip extracts a zip file (src) to the dst directory.
-func extractZip(src, dst string) error {
+// extractZip extracts the entries selected by filter of a zip file (src) to
+// the dst directory. Symbolic links are extracted if their targets are within
+// dst. Permission bits and modification times are preserved.
+func extractZip(src, dst string, filter Filter, l *Limiter) error {
 	zrc, err := zip.OpenReader(src)
 	if err != nil {
 		return err
 	}
 	defer zrc.Close()
 
+	dirs := dirMetadata{}
+
 	for _, f := range zrc.File {
-		target := filepath.Join(dst, f.Name)
-		rel, relErr := filepath.Rel(dst, target)
-		if relErr != nil || strings.HasPrefix(rel, "..") {
-			return fmt.Errorf("detected unsafe file in archive (zip slip)")
+		if err := l.Entry(); err != nil {
+			return err
+		}
+
+		target, err := safePath(dst, f.Name)
+		if err != nil {
+			return err
+		}
+		if !filter.selects(f.Name) {
+			continue
 		}
 
-		if f.FileInfo().IsDir() {
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
 			err = os.MkdirAll(target, 0o755)
 			if err != nil {
 				return err
 			}
-			continue
+			dirs.set(target, mode.Perm(), f.Modified)
+		case mode&fs.ModeSymlink != 0:
+			linkname, err := readZipLink(f)
+			if err != nil {
+				return err
+			}
+			err = extractSymlink(dst, target, linkname)
+			if err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			err = os.MkdirAll(filepath.Dir(target), 0o755)
+			if err != nil {
+				return err
+			}
+			err = copyFileFromZip(f, target, l)
+			if err != nil {
+				return err
+			}
+			err = setFileMetadata(target, mode.Perm(), f.Modified)
+			if err != nil {
+				return err
+			}
 		}
+	}
 
-		err = os.MkdirAll(filepath.Dir(target), 0o755)
-		if err != nil {
-			return err
-		}
+	return dirs.apply()
+}
 
-		err = copyFileFromZip(f, target)
-		if err != nil {
-			return err
-		}
+// readZipLink returns the target of a symbolic link within a zip archive,
+// which is stored as the content of the entry.
+func readZipLink(z *zip.File) (string, error) {
+	zrc, err := z.Open()
+	if err != nil {
+		return "", err
 	}
+	defer zrc.Close()
 
-	return nil
+	data, err := io.ReadAll(io.LimitReader(zrc, maxLinkBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxLinkBytes {
+		return "", fmt.Errorf("archive link %s exceeds size limit of %d bytes", z.Name, maxLinkBytes)
+	}
+	return string(data), nil
 }
 
 // copyFileFromZip copies a file within a zip archive to the target path.
-func copyFileFromZip(z *zip.File, dst string) (retErr error) {
+func copyFileFromZip(z *zip.File, dst string, l *Limiter) (retErr error) {
+	if err := l.checkSize(int64(z.UncompressedSize64)); err != nil {
+		return err
+	}
+
 	zrc, err := z.Open()
 	if err != nil {
 		return err
 	}
 	defer zrc.Close()
 
-	df, err := os.OpenFile(dst, os.O_CREATE|os.O_RDWR, z.Mode())
+	if err := removeNonDir(dst); err != nil {
+		return err
+	}
+
+	df, err := os.OpenFile(dst, os.O_CREATE|os.O_RDWR|os.O_TRUNC, z.Mode().Perm())
 	if err != nil {
 		return err
 	}
@@ -80,13 +131,6 @@ func copyFileFromZip(z *zip.File, dst string) (retErr error) {
 		}
 	}()
 
-	lr := &io.LimitedReader{R: zrc, N: maxExtractBytes + 1}
-	if _, err = io.Copy(df, lr); err != nil {
-		return err
-	}
-	if lr.N == 0 {
-		return fmt.Errorf("archive entry exceeds size limit of %d bytes", maxExtractBytes)
-	}
-
-	return nil
+	_, err = l.Copy(df, zrc)
+	return err
 }
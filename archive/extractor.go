@@ -0,0 +1,154 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// detectBytes is the number of bytes read from the start of an archive to
+// detect its format.
+const detectBytes = 512
+
+// An Extractor extracts archives of one format.
+type Extractor interface {
+	// Name returns the name of the archive format (e.g. tar.gz).
+	Name() string
+
+	// Detect reports whether header, up to the first 512 bytes of a file,
+	// identifies an archive of this format.
+	Detect(header []byte) bool
+
+	// Extract unpacks the entries of the archive src selected by filter
+	// into the dst directory, calling the Entry method of l for each entry
+	// and copying the content of each file with its Copy method.
+	Extract(src, dst string, filter Filter, l *Limiter) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Extractor
+)
+
+// Register adds e to the registry of extractors used by Extract. Extractors
+// are consulted in the order in which they were registered, so an extractor
+// for a format whose header is a special case of another format's header
+// must be registered first.
+func Register(e Extractor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, e)
+}
+
+// Extractors returns the registered extractors in the order in which they are
+// consulted.
+func Extractors() []Extractor {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]Extractor(nil), registry...)
+}
+
+// Detect returns the registered extractor for the archive src, identified by
+// the magic bytes at the start of the file rather than its extension.
+func Detect(src string) (Extractor, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	header := make([]byte, detectBytes)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	header = header[:n]
+
+	for _, e := range Extractors() {
+		if e.Detect(header) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown archive format")
+}
+
+// extractor is an Extractor implemented by functions.
+type extractor struct {
+	name    string
+	detect  func(header []byte) bool
+	extract func(src, dst string, filter Filter, l *Limiter) error
+}
+
+// Name implements Extractor.
+func (e extractor) Name() string { return e.name }
+
+// Detect implements Extractor.
+func (e extractor) Detect(header []byte) bool { return e.detect(header) }
+
+// Extract implements Extractor.
+func (e extractor) Extract(src, dst string, filter Filter, l *Limiter) error {
+	return e.extract(src, dst, filter, l)
+}
+
+// Magic numbers identifying archive and compression formats.
+var (
+	magicGzip     = []byte{0x1f, 0x8b}
+	magicXz       = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	magicZstd     = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	magicZip      = []byte("PK\x03\x04")
+	magicZipEmpty = []byte("PK\x05\x06") // end of central directory only
+	magicTar      = []byte("ustar")      // at offset 257
+)
+
+// hasMagic returns a function that reports whether a header begins with
+// magic.
+func hasMagic(magic []byte) func([]byte) bool {
+	return func(header []byte) bool {
+		return bytes.HasPrefix(header, magic)
+	}
+}
+
+// isDeb reports whether header is that of a Debian package: an ar archive
+// whose first member is debian-binary.
+func isDeb(header []byte) bool {
+	return bytes.HasPrefix(header, []byte(arMagic+"debian-binary"))
+}
+
+// isZip reports whether header is that of a zip file.
+func isZip(header []byte) bool {
+	return bytes.HasPrefix(header, magicZip) || bytes.HasPrefix(header, magicZipEmpty)
+}
+
+// isTar reports whether header is that of a POSIX or GNU tarball.
+func isTar(header []byte) bool {
+	return len(header) >= 257+len(magicTar) && bytes.Equal(header[257:257+len(magicTar)], magicTar)
+}
+
+// init registers the built-in extractors.
+func init() {
+	Register(extractor{"pkg", hasMagic([]byte(xarMagic)), extractPkg})
+	Register(extractor{"deb", isDeb, extractDeb})
+	Register(extractor{"zip", isZip, extractZip})
+	Register(extractor{"tar.gz", hasMagic(magicGzip), extractTarGZ})
+	Register(extractor{"tar.xz", hasMagic(magicXz), extractTarXZ})
+	Register(extractor{"tar.zst", hasMagic(magicZstd), extractTarZst})
+	Register(extractor{"tar", isTar, extractTarFile})
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"path"
+	"strings"
+)
+
+// A Filter reports whether to extract the archive entry with the given name,
+// a slash-separated path relative to the root of the archive (e.g.
+// usr/local/bin/hugo). A nil Filter extracts every entry. The directories
+// that contain a selected entry are created whether or not they are
+// selected. Every entry, selected or not, counts toward Limits.MaxEntries.
+type Filter func(name string) bool
+
+// licensePrefixes are the lower-case prefixes of the names of license files.
+var licensePrefixes = []string{"copying", "licence", "license", "notice"}
+
+// ExecutableFilter returns a Filter that selects the file named execName, in
+// any directory of the archive, and, if licenses is true, license files such
+// as LICENSE, LICENSE.md, and COPYING.
+func ExecutableFilter(execName string, licenses bool) Filter {
+	return func(name string) bool {
+		base := path.Base(name)
+		if base == execName {
+			return true
+		}
+		return licenses && isLicenseFile(base)
+	}
+}
+
+// isLicenseFile reports whether the base name of a file identifies it as a
+// license file.
+func isLicenseFile(base string) bool {
+	base = strings.ToLower(base)
+	for _, p := range licensePrefixes {
+		if strings.HasPrefix(base, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// selects reports whether f selects the archive entry with the given name,
+// which is cleaned of any leading "./" or "/" and trailing "/" first.
+func (f Filter) selects(name string) bool {
+	if f == nil {
+		return true
+	}
+	return f(strings.TrimPrefix(path.Clean("/"+name), "/"))
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"fmt"
+	"io"
+)
+
+// Limits bounds the resources used to extract an archive, guarding against
+// decompression bombs. A limit of zero or less is not enforced.
+type Limits struct {
+	MaxEntryBytes int64 // Maximum number of bytes written from a single entry
+	MaxTotalBytes int64 // Maximum number of bytes written from all entries
+	MaxEntries    int64 // Maximum number of entries, including directories and links
+	MaxRatio      int64 // Maximum ratio of the number of bytes written to the size of the archive
+}
+
+// DefaultLimits are the limits used by Extract.
+var DefaultLimits = Limits{
+	MaxEntryBytes: 500 << 20, // 500 MB
+	MaxTotalBytes: 4 << 30,   // 4 GB
+	MaxEntries:    10000,
+	MaxRatio:      100,
+}
+
+// minRatioBytes is the number of bytes that must be written before the
+// compression ratio limit is enforced, so that small archives of highly
+// compressible files are not rejected.
+const minRatioBytes = 1 << 20 // 1 MB
+
+// A Limit identifies one of the limits in Limits.
+type Limit int
+
+// The limits that may be exceeded during extraction.
+const (
+	LimitEntryBytes Limit = iota // Limits.MaxEntryBytes
+	LimitTotalBytes              // Limits.MaxTotalBytes
+	LimitEntries                 // Limits.MaxEntries
+	LimitRatio                   // Limits.MaxRatio
+)
+
+// A LimitError is returned when extracting an archive exceeds one of its
+// limits.
+type LimitError struct {
+	Limit Limit // The limit that was exceeded
+	Max   int64 // The value of the limit
+}
+
+// Error implements error.
+func (e *LimitError) Error() string {
+	switch e.Limit {
+	case LimitEntryBytes:
+		return fmt.Sprintf("archive entry exceeds size limit of %d bytes", e.Max)
+	case LimitTotalBytes:
+		return fmt.Sprintf("archive exceeds total size limit of %d bytes", e.Max)
+	case LimitEntries:
+		return fmt.Sprintf("archive exceeds limit of %d entries", e.Max)
+	case LimitRatio:
+		return fmt.Sprintf("archive exceeds compression ratio limit of %d", e.Max)
+	}
+	return "archive exceeds extraction limit"
+}
+
+// A Limiter enforces Limits across all of the entries of an archive. An
+// Extractor calls Entry for each entry in the archive, and writes the content
+// of each file with Copy.
+type Limiter struct {
+	limits      Limits
+	archiveSize int64
+	entries     int64
+	total       int64
+}
+
+// NewLimiter returns a Limiter that enforces limits for an archive of
+// archiveSize bytes.
+func NewLimiter(limits Limits, archiveSize int64) *Limiter {
+	return &Limiter{limits: limits, archiveSize: archiveSize}
+}
+
+// Entry records an entry of the archive, returning a *LimitError if the
+// number of entries exceeds the limit.
+func (l *Limiter) Entry() error {
+	l.entries++
+	if l.limits.MaxEntries > 0 && l.entries > l.limits.MaxEntries {
+		return &LimitError{Limit: LimitEntries, Max: l.limits.MaxEntries}
+	}
+	return nil
+}
+
+// Copy copies the content of an entry from src to dst, returning a
+// *LimitError as soon as the entry, or the archive as a whole, exceeds a
+// limit.
+func (l *Limiter) Copy(dst io.Writer, src io.Reader) (int64, error) {
+	var written int64
+	buf := make([]byte, 32<<10)
+	for {
+		n, rerr := src.Read(buf)
+		if n > 0 {
+			written += int64(n)
+			l.total += int64(n)
+			if err := l.check(written); err != nil {
+				return written, err
+			}
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return written, err
+			}
+		}
+		if rerr == io.EOF {
+			return written, nil
+		}
+		if rerr != nil {
+			return written, rerr
+		}
+	}
+}
+
+// checkSize returns a *LimitError if an entry of size bytes would exceed the
+// entry size limit. It allows an extractor to reject an entry before
+// reading it.
+func (l *Limiter) checkSize(size int64) error {
+	if l.limits.MaxEntryBytes > 0 && size > l.limits.MaxEntryBytes {
+		return &LimitError{Limit: LimitEntryBytes, Max: l.limits.MaxEntryBytes}
+	}
+	return nil
+}
+
+// check returns a *LimitError if the current entry, having written n bytes,
+// or the archive as a whole exceeds a limit.
+func (l *Limiter) check(n int64) error {
+	if err := l.checkSize(n); err != nil {
+		return err
+	}
+	if l.limits.MaxTotalBytes > 0 && l.total > l.limits.MaxTotalBytes {
+		return &LimitError{Limit: LimitTotalBytes, Max: l.limits.MaxTotalBytes}
+	}
+	if l.limits.MaxRatio > 0 && l.archiveSize > 0 && l.total > minRatioBytes && l.total > l.limits.MaxRatio*l.archiveSize {
+		return &LimitError{Limit: LimitRatio, Max: l.limits.MaxRatio}
+	}
+	return nil
+}
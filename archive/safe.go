@@ -0,0 +1,212 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxLinkBytes is the maximum length of a link target in an archive.
+const maxLinkBytes = 4096
+
+// errZipSlip is returned when an archive entry would be extracted outside of
+// the destination directory.
+var errZipSlip = errors.New("detected unsafe file in archive (zip slip)")
+
+// safePath returns the path at which the archive entry name is extracted to
+// the dst directory. It returns an error if the path is outside of dst, or if
+// any of its parent directories within dst is a symbolic link, so that an
+// entry cannot be written through a link extracted earlier.
+func safePath(dst, name string) (string, error) {
+	target := filepath.Join(dst, name)
+	rel, err := filepath.Rel(dst, target)
+	if err != nil || !filepath.IsLocal(rel) && rel != "." {
+		return "", errZipSlip
+	}
+	if rel == "." {
+		return target, nil
+	}
+
+	dir := dst
+	parts := strings.Split(rel, string(filepath.Separator))
+	for _, part := range parts[:len(parts)-1] {
+		dir = filepath.Join(dir, part)
+		fi, err := os.Lstat(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			return "", fmt.Errorf("detected unsafe file in archive (%s is written through a link)", name)
+		}
+	}
+	return target, nil
+}
+
+// safeLinkTarget returns the target to use for a symbolic link extracted to
+// the path link within the dst directory, where target is the link target
+// recorded in the archive. It returns an error if target is absolute or
+// refers to a path outside of dst. The returned target is the equivalent
+// clean relative path, so that the link resolves within dst even if it is
+// followed through other links.
+func safeLinkTarget(dst, link, target string) (string, error) {
+	t := filepath.FromSlash(target)
+	if target == "" || filepath.IsAbs(t) || strings.HasPrefix(target, "/") || filepath.VolumeName(t) != "" {
+		return "", fmt.Errorf("detected unsafe link in archive (%s -> %s)", filepath.Base(link), target)
+	}
+
+	resolved := filepath.Join(filepath.Dir(link), t)
+	rel, err := filepath.Rel(dst, resolved)
+	if err != nil || !filepath.IsLocal(rel) && rel != "." {
+		return "", fmt.Errorf("detected unsafe link in archive (%s -> %s)", filepath.Base(link), target)
+	}
+
+	return filepath.Rel(filepath.Dir(link), resolved)
+}
+
+// extractSymlink creates a symbolic link at the path link within the dst
+// directory, replacing any existing file other than a directory.
+func extractSymlink(dst, link, target string) error {
+	t, err := safeLinkTarget(dst, link, target)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+		return err
+	}
+	if err := removeNonDir(link); err != nil {
+		return err
+	}
+	return os.Symlink(t, link)
+}
+
+// extractHardlink creates a hard link at the path link within the dst
+// directory to the previously extracted regular file name, a path relative to
+// the root of the archive. If the file system does not support hard links, the
+// file is copied instead.
+func extractHardlink(dst, link, name string, l *Limiter) error {
+	src, err := safePath(dst, name)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Lstat(src)
+	if err != nil || !fi.Mode().IsRegular() {
+		return fmt.Errorf("detected unsafe link in archive (%s => %s is not a regular file in the archive)", filepath.Base(link), name)
+	}
+	if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+		return err
+	}
+	if err := removeNonDir(link); err != nil {
+		return err
+	}
+	if err := os.Link(src, link); err == nil {
+		return nil
+	}
+	return copyRegularFile(src, link, fi.Mode().Perm(), l)
+}
+
+// removeNonDir removes the file at path if it exists and is not a directory,
+// so that extracting an entry never writes through an existing link.
+func removeNonDir(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return fmt.Errorf("cannot replace directory %s", path)
+	}
+	return os.Remove(path)
+}
+
+// copyRegularFile copies the regular file src to dst with permission bits
+// perm.
+func copyRegularFile(src, dst string, perm fs.FileMode, l *Limiter) (retErr error) {
+	sf, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sf.Close()
+
+	df, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := df.Close(); cerr != nil && retErr == nil {
+			retErr = cerr
+		}
+	}()
+
+	if _, err := l.Copy(df, sf); err != nil {
+		return err
+	}
+	return os.Chmod(dst, perm)
+}
+
+// dirMetadata records the permission bits and modification times of
+// extracted directories, keyed by path. They are applied after all entries
+// are extracted, because extracting an entry into a directory changes the
+// directory's modification time and may require permissions that the
+// directory does not grant.
+type dirMetadata map[string]struct {
+	perm  fs.FileMode
+	mtime time.Time
+}
+
+// set records the permission bits and modification time of the directory
+// path.
+func (d dirMetadata) set(path string, perm fs.FileMode, mtime time.Time) {
+	d[path] = struct {
+		perm  fs.FileMode
+		mtime time.Time
+	}{perm, mtime}
+}
+
+// apply sets the recorded permission bits and modification times. The owner
+// always retains full access so that the extracted files can be copied and
+// removed.
+func (d dirMetadata) apply() error {
+	for path, m := range d {
+		if err := setFileMetadata(path, m.perm|0o700, m.mtime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFileMetadata sets the permission bits and, if not zero, the modification
+// time of the extracted file path.
+func setFileMetadata(path string, perm fs.FileMode, mtime time.Time) error {
+	if err := os.Chmod(path, perm); err != nil {
+		return err
+	}
+	if mtime.IsZero() {
+		return nil
+	}
+	return os.Chtimes(path, time.Time{}, mtime)
+}
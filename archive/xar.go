@@ -0,0 +1,181 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+)
+
+// xarMagic is the magic number at the start of a xar archive.
+const xarMagic = "xar!"
+
+// maxXarTOCBytes is the maximum size of the uncompressed table of contents of
+// a xar archive.
+const maxXarTOCBytes = 16 << 20 // 16 MB
+
+// xarHeader is the fixed-size header at the start of a xar archive. All fields
+// are big-endian.
+type xarHeader struct {
+	Magic             [4]byte
+	HeaderSize        uint16
+	Version           uint16
+	TOCLengthZipped   uint64
+	TOCLengthUnzip    uint64
+	ChecksumAlgorithm uint32
+}
+
+// xarTOC is the table of contents of a xar archive.
+type xarTOC struct {
+	Files []xarFile `xml:"toc>file"`
+}
+
+// xarFile is a file or directory in a xar table of contents.
+type xarFile struct {
+	Name  string    `xml:"name"`
+	Type  string    `xml:"type"`
+	Data  *xarData  `xml:"data"`
+	Files []xarFile `xml:"file"`
+}
+
+// xarData describes the location and encoding of a file's data in the heap.
+type xarData struct {
+	Offset   int64 `xml:"offset"`
+	Length   int64 `xml:"length"`
+	Size     int64 `xml:"size"`
+	Encoding struct {
+		Style string `xml:"style,attr"`
+	} `xml:"encoding"`
+}
+
+// xarReader reads files from a xar archive.
+type xarReader struct {
+	r        io.ReaderAt
+	size     int64
+	heapBase int64
+	toc      xarTOC
+}
+
+// openXar reads the header and table of contents of the xar archive r, which
+// is size bytes long.
+func openXar(r io.ReaderAt, size int64) (*xarReader, error) {
+	var h xarHeader
+	err := binary.Read(io.NewSectionReader(r, 0, size), binary.BigEndian, &h)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xar header: %w", err)
+	}
+	if string(h.Magic[:]) != xarMagic {
+		return nil, fmt.Errorf("not a xar archive")
+	}
+	if int64(h.HeaderSize) < int64(binary.Size(h)) {
+		return nil, fmt.Errorf("invalid xar header size %d", h.HeaderSize)
+	}
+	if h.TOCLengthUnzip > maxXarTOCBytes || h.TOCLengthZipped > uint64(size) {
+		return nil, fmt.Errorf("xar table of contents exceeds size limit of %d bytes", maxXarTOCBytes)
+	}
+
+	tocStart := int64(h.HeaderSize)
+	tocEnd := tocStart + int64(h.TOCLengthZipped)
+	if tocEnd > size {
+		return nil, fmt.Errorf("truncated xar table of contents")
+	}
+
+	zr, err := zlib.NewReader(io.NewSectionReader(r, tocStart, int64(h.TOCLengthZipped)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid xar table of contents: %w", err)
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(io.LimitReader(zr, maxXarTOCBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid xar table of contents: %w", err)
+	}
+	if len(data) > maxXarTOCBytes {
+		return nil, fmt.Errorf("xar table of contents exceeds size limit of %d bytes", maxXarTOCBytes)
+	}
+
+	x := &xarReader{r: r, size: size, heapBase: tocEnd}
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&x.toc); err != nil {
+		return nil, fmt.Errorf("invalid xar table of contents: %w", err)
+	}
+	return x, nil
+}
+
+// find returns the first regular file named name, searching the table of
+// contents breadth first so that a top-level file is preferred over a file of
+// the same name in a subdirectory.
+func (x *xarReader) find(name string) (*xarFile, bool) {
+	queue := x.toc.Files
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		if f.Name == name && f.Type == "file" {
+			return &f, true
+		}
+		queue = append(queue, f.Files...)
+	}
+	return nil, false
+}
+
+// open returns a reader for the decoded data of f.
+func (x *xarReader) open(f *xarFile) (io.ReadCloser, error) {
+	if f.Data == nil {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	d := f.Data
+	if d.Offset < 0 || d.Length < 0 || x.heapBase+d.Offset+d.Length > x.size {
+		return nil, fmt.Errorf("xar entry %s is outside of the archive", f.Name)
+	}
+	sr := io.NewSectionReader(x.r, x.heapBase+d.Offset, d.Length)
+
+	switch d.Encoding.Style {
+	case "", "application/octet-stream":
+		return io.NopCloser(sr), nil
+	case "application/x-gzip":
+		// Despite its name, this encoding is a zlib stream.
+		return zlib.NewReader(sr)
+	case "application/x-bzip2":
+		return io.NopCloser(bzip2.NewReader(sr)), nil
+	}
+	return nil, fmt.Errorf("xar entry %s has unsupported encoding %s", f.Name, d.Encoding.Style)
+}
+
+// openXarFile opens the named xar archive. The caller must close the returned
+// file.
+func openXarFile(name string) (*xarReader, *os.File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	x, err := openXar(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return x, f, nil
+}
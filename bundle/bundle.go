@@ -0,0 +1,366 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle exports cached builds to, and imports cached builds from, a
+// portable bundle file for use on machines without network access.
+//
+// A bundle is a gzipped tarball containing a descriptor (bundle.json) and, for
+// each build, the content of its cache directory under builds/<tag>/<edition>,
+// including the build's manifest. The builds in a bundle are for a single
+// platform, recorded in the descriptor.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmooring/hvm/archive"
+	"github.com/jmooring/hvm/cache"
+	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/repository"
+	"golang.org/x/mod/semver"
+)
+
+// SchemaVersion is the version of the bundle format.
+const SchemaVersion = 1
+
+const (
+	descriptorFileName = "bundle.json"
+	buildsDirName      = "builds"
+)
+
+// A Descriptor describes the content of a bundle.
+type Descriptor struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	OS            string    `json:"os"`     // operating system of the builds (e.g. linux)
+	Arch          string    `json:"arch"`   // architecture of the builds (e.g. amd64)
+	Builds        []string  `json:"builds"` // tag/edition pairs (e.g. v0.153.0/extended)
+	Tags          []string  `json:"tags"`   // release tags, newest first
+	Created       time.Time `json:"created"`
+}
+
+// Export writes a bundle to dst containing the cached builds for platform p
+// identified by buildIDs, each a tag/edition pair, and the given release
+// tags, and returns
+// its descriptor. A build identified more than once is written once. A
+// manifest is created for any build cached without one. If writing the bundle
+// fails, dst is removed.
+func Export(dst, cacheDirPath string, p repository.Platform, buildIDs, tags []string) (_ *Descriptor, retErr error) {
+	if len(buildIDs) == 0 {
+		return nil, fmt.Errorf("no builds to export")
+	}
+
+	d := Descriptor{
+		SchemaVersion: SchemaVersion,
+		OS:            p.OS,
+		Arch:          p.Arch,
+		Tags:          tags,
+		Created:       time.Now().UTC(),
+	}
+
+	for _, id := range buildIDs {
+		tag, edition, err := splitBuildID(id)
+		if err != nil {
+			return nil, err
+		}
+		id = tag + "/" + edition
+		if slices.Contains(d.Builds, id) {
+			continue
+		}
+		exists, err := helpers.Exists(cache.BuildDirPath(cacheDirPath, p.OS, p.Arch, tag, edition))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if p != repository.HostPlatform() {
+				return nil, fmt.Errorf("%s for %s is not cached", id, p)
+			}
+			return nil, fmt.Errorf("%s is not cached", id)
+		}
+		d.Builds = append(d.Builds, id)
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+		if retErr != nil {
+			os.Remove(dst) // do not leave a truncated bundle
+		}
+	}()
+
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = writeFile(tw, descriptorFileName, 0o644, d.Created, data)
+	if err != nil {
+		return nil, err
+	}
+	err = writeDir(tw, buildsDirName, d.Created)
+	if err != nil {
+		return nil, err
+	}
+
+	written := map[string]bool{}
+	for _, id := range d.Builds {
+		tag, edition, _ := strings.Cut(id, "/")
+		if !written[tag] {
+			err := writeDir(tw, path.Join(buildsDirName, tag), d.Created)
+			if err != nil {
+				return nil, err
+			}
+			written[tag] = true
+		}
+		err := writeBuild(tw, cache.BuildDirPath(cacheDirPath, p.OS, p.Arch, tag, edition), path.Join(buildsDirName, tag, edition), tag, edition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzw.Close(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// writeBuild writes the content of the build directory dir to tw under name,
+// along with its manifest.
+func writeBuild(tw *tar.Writer, dir, name, tag, edition string) error {
+	m, err := cache.ReadManifest(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		m, err = cache.NewManifest(dir, tag, edition)
+		if err != nil {
+			return err
+		}
+	}
+
+	return fs.WalkDir(os.DirFS(dir), ".", func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		fi, err := de.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case p == cache.ManifestFileName:
+			return nil // written from m below
+		case de.IsDir():
+			err := writeDir(tw, path.Join(name, p), fi.ModTime())
+			if err != nil {
+				return err
+			}
+			if p == "." {
+				data, err := json.MarshalIndent(m, "", "  ")
+				if err != nil {
+					return err
+				}
+				return writeFile(tw, path.Join(name, cache.ManifestFileName), 0o644, fi.ModTime(), data)
+			}
+			return nil
+		case !de.Type().IsRegular():
+			return nil
+		}
+		return copyFile(tw, path.Join(name, p), filepath.Join(dir, filepath.FromSlash(p)), fi)
+	})
+}
+
+// copyFile writes a regular file entry to tw for the file src, described by
+// fi, copying its content rather than reading it into memory.
+func copyFile(tw *tar.Writer, name, src string, fi fs.FileInfo) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(fi.Mode().Perm()),
+		Size:     fi.Size(),
+		ModTime:  fi.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// writeDir writes a directory entry to tw.
+func writeDir(tw *tar.Writer, name string, modTime time.Time) error {
+	return tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     path.Clean(name) + "/",
+		Mode:     0o755,
+		ModTime:  modTime,
+	})
+}
+
+// writeFile writes a regular file entry to tw.
+func writeFile(tw *tar.Writer, name string, mode int64, modTime time.Time, data []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     mode,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+// Import verifies the builds in the bundle src against their manifests and
+// moves them into the cache for the platform of the bundle, replacing existing
+// builds with the same tag and edition. The bundle is extracted to a temporary directory within
+// stagingDirPath, which must be on the same file system as cacheDirPath, so
+// that each build is committed to the cache by renaming its directory, and a
+// failed import never leaves a partial build in the cache. Nothing is
+// committed unless every build is verified. It returns the bundle descriptor.
+// Extracting the bundle is subject to limits.
+func Import(src, cacheDirPath, stagingDirPath string, limits archive.Limits) (*Descriptor, error) {
+	err := os.MkdirAll(stagingDirPath, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp(stagingDirPath, "")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	archivePath := filepath.Join(tmp, "bundle.tar.gz")
+	err = helpers.CopyFile(src, archivePath)
+	if err != nil {
+		return nil, err
+	}
+	extractDir := filepath.Join(tmp, "bundle")
+	err = os.Mkdir(extractDir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	err = archive.ExtractWithLimits(archivePath, extractDir, true, nil, limits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle %s: %w", src, err)
+	}
+
+	d, err := readDescriptor(filepath.Join(extractDir, descriptorFileName))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle %s: %w", src, err)
+	}
+
+	for _, id := range d.Builds {
+		tag, edition, err := splitBuildID(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle %s: %w", src, err)
+		}
+		dir := filepath.Join(extractDir, buildsDirName, tag, edition)
+		m, err := cache.ReadManifest(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle %s: %s: %w", src, id, err)
+		}
+		if m.Tag != tag || m.Edition != edition {
+			return nil, fmt.Errorf("invalid bundle %s: manifest for %s describes %s/%s", src, id, m.Tag, m.Edition)
+		}
+		err = m.Verify(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle %s: %w", src, err)
+		}
+	}
+
+	for _, id := range d.Builds {
+		tag, edition, _ := splitBuildID(id)
+		err := cache.CommitBuild(filepath.Join(extractDir, buildsDirName, tag, edition), cache.BuildDirPath(cacheDirPath, d.OS, d.Arch, tag, edition))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// readDescriptor reads and validates the bundle descriptor in the named file.
+func readDescriptor(name string) (*Descriptor, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var d Descriptor
+	if err := json.NewDecoder(io.LimitReader(f, 1<<20)).Decode(&d); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", descriptorFileName, err)
+	}
+	if d.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d", d.SchemaVersion)
+	}
+	if p := (repository.Platform{OS: d.OS, Arch: d.Arch}); !slices.Contains(repository.Platforms, p) {
+		return nil, fmt.Errorf("unsupported platform %q", p)
+	}
+	if len(d.Builds) == 0 {
+		return nil, fmt.Errorf("no builds")
+	}
+	for i, id := range d.Builds {
+		if slices.Contains(d.Builds[:i], id) {
+			return nil, fmt.Errorf("duplicate build %q", id)
+		}
+	}
+	return &d, nil
+}
+
+// splitBuildID splits a tag/edition pair, adding the "v" prefix to the tag if
+// missing, and reports an error if either part is invalid.
+func splitBuildID(id string) (tag, edition string, err error) {
+	tag, edition, ok := strings.Cut(id, "/")
+	if !ok || !slices.Contains(repository.ValidEditions, edition) {
+		return "", "", fmt.Errorf("invalid build %q: must be a version/edition pair (e.g. v0.153.0/extended)", id)
+	}
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	if !semver.IsValid(tag) {
+		return "", "", fmt.Errorf("invalid build %q: invalid version", id)
+	}
+	return tag, edition, nil
+}
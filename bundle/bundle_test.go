@@ -0,0 +1,271 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jmooring/hvm/archive"
+	"github.com/jmooring/hvm/cache"
+	"github.com/jmooring/hvm/repository"
+)
+
+func TestExportImport(t *testing.T) {
+	src := t.TempDir()
+	cacheBuild(t, src, "v0.153.0", "extended", true)
+	cacheBuild(t, src, "v0.152.0", "standard", false) // cached without a manifest
+
+	bundlePath := filepath.Join(t.TempDir(), "hugo-bundle.tar.gz")
+	tags := []string{"v0.153.0", "v0.152.0"}
+	_, err := Export(bundlePath, src, repository.HostPlatform(), []string{"v0.153.0/extended", "0.152.0/standard", "v0.153.0/extended"}, tags)
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	dst := t.TempDir()
+	d, err := Import(bundlePath, dst, filepath.Join(dst, "staging"), archive.DefaultLimits)
+	if err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	if want := []string{"v0.153.0/extended", "v0.152.0/standard"}; !slices.Equal(d.Builds, want) {
+		t.Errorf("Import() builds: want %v got %v", want, d.Builds)
+	}
+	if !slices.Equal(d.Tags, tags) {
+		t.Errorf("Import() tags: want %v got %v", tags, d.Tags)
+	}
+
+	for _, id := range d.Builds {
+		dir := filepath.Join(dst, filepath.FromSlash(id))
+		m, err := cache.ReadManifest(dir)
+		if err != nil {
+			t.Fatalf("ReadManifest(%s) error: %v", id, err)
+		}
+		if err := m.Verify(dir); err != nil {
+			t.Errorf("Verify(%s) error: %v", id, err)
+		}
+	}
+
+	fi, err := os.Stat(filepath.Join(dst, "v0.153.0", "extended", "hugo"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if fi.Mode().Perm()&0o100 == 0 {
+		t.Errorf("Import(): executable permission not preserved: %v", fi.Mode())
+	}
+}
+
+func TestExportImport_OtherPlatform(t *testing.T) {
+	p := repository.Platform{OS: "windows", Arch: "arm64"}
+	if p == repository.HostPlatform() {
+		p = repository.Platform{OS: "linux", Arch: "amd64"}
+	}
+
+	// Move a build to the cache directory for p.
+	src := t.TempDir()
+	cacheBuild(t, src, "v0.153.0", "extended", true)
+	dir := cache.BuildDirPath(src, p.OS, p.Arch, "v0.153.0", "extended")
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.Rename(filepath.Join(src, "v0.153.0", "extended"), dir); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "hugo-bundle.tar.gz")
+	if _, err := Export(bundlePath, src, repository.HostPlatform(), []string{"v0.153.0/extended"}, nil); err == nil {
+		t.Fatal("Export() expected error for build not cached for the host")
+	}
+	if _, err := Export(bundlePath, src, p, []string{"v0.153.0/extended"}, nil); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	dst := t.TempDir()
+	d, err := Import(bundlePath, dst, filepath.Join(dst, "staging"), archive.DefaultLimits)
+	if err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	if d.OS != p.OS || d.Arch != p.Arch {
+		t.Errorf("Import() platform: want %s got %s/%s", p, d.OS, d.Arch)
+	}
+	if _, err := os.Stat(cache.BuildDirPath(dst, p.OS, p.Arch, "v0.153.0", "extended")); err != nil {
+		t.Errorf("Import() did not cache the build for %s: %v", p, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "v0.153.0")); !os.IsNotExist(err) {
+		t.Errorf("Import() cached the build for the host")
+	}
+}
+
+func TestExport_NotCached(t *testing.T) {
+	_, err := Export(filepath.Join(t.TempDir(), "b.tar.gz"), t.TempDir(), repository.HostPlatform(), []string{"v0.153.0/extended"}, nil)
+	if err == nil {
+		t.Fatal("Export() expected error for uncached build")
+	}
+}
+
+func TestExport_RemovesPartialBundle(t *testing.T) {
+	src := t.TempDir()
+	cacheBuild(t, src, "v0.153.0", "extended", true)
+
+	// The manifest of the build is invalid, so writing the bundle fails part
+	// way.
+	if err := os.WriteFile(filepath.Join(src, "v0.153.0", "extended", cache.ManifestFileName), []byte("{"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	bundlePath := filepath.Join(t.TempDir(), "hugo-bundle.tar.gz")
+	if _, err := Export(bundlePath, src, repository.HostPlatform(), []string{"v0.153.0/extended"}, nil); err == nil {
+		t.Fatal("Export() expected error")
+	}
+	if _, err := os.Stat(bundlePath); !os.IsNotExist(err) {
+		t.Fatal("Export() left a partial bundle")
+	}
+}
+
+func TestExport_InvalidBuildID(t *testing.T) {
+	for _, id := range []string{"v0.153.0", "v0.153.0/", "latest/extended", "v0.153.0/../x", "v0.153.0/anything"} {
+		_, err := Export(filepath.Join(t.TempDir(), "b.tar.gz"), t.TempDir(), repository.HostPlatform(), []string{id}, nil)
+		if err == nil {
+			t.Errorf("Export(%q) expected error", id)
+		}
+	}
+}
+
+func TestImport_Tampered(t *testing.T) {
+	src := t.TempDir()
+	cacheBuild(t, src, "v0.153.0", "extended", true)
+
+	// Modify the build after its manifest was written.
+	err := os.WriteFile(filepath.Join(src, "v0.153.0", "extended", "hugo"), []byte("tampered"), 0o755)
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "hugo-bundle.tar.gz")
+	if _, err := Export(bundlePath, src, repository.HostPlatform(), []string{"v0.153.0/extended"}, nil); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	dst := t.TempDir()
+	if _, err := Import(bundlePath, dst, filepath.Join(dst, "staging"), archive.DefaultLimits); err == nil {
+		t.Fatal("Import() expected error for tampered build")
+	}
+	if _, err := os.Stat(filepath.Join(dst, "v0.153.0")); !os.IsNotExist(err) {
+		t.Fatalf("Import() copied a build that failed verification")
+	}
+}
+
+func TestImport_ReplacesBuild(t *testing.T) {
+	src := t.TempDir()
+	cacheBuild(t, src, "v0.153.0", "extended", true)
+
+	bundlePath := filepath.Join(t.TempDir(), "hugo-bundle.tar.gz")
+	if _, err := Export(bundlePath, src, repository.HostPlatform(), []string{"v0.153.0/extended"}, nil); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	// A file left over from an earlier build must not survive the import.
+	dst := t.TempDir()
+	cacheBuild(t, dst, "v0.153.0", "extended", false)
+	stale := filepath.Join(dst, "v0.153.0", "extended", "stale")
+	if err := os.WriteFile(stale, []byte("stale"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	staging := filepath.Join(dst, "staging")
+	if _, err := Import(bundlePath, dst, staging, archive.DefaultLimits); err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("Import() merged the build into an existing build")
+	}
+	dir := filepath.Join(dst, "v0.153.0", "extended")
+	m, err := cache.ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("ReadManifest() error: %v", err)
+	}
+	if err := m.Verify(dir); err != nil {
+		t.Errorf("Verify() error: %v", err)
+	}
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		t.Fatalf("ReadDir() error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Import() left %d entries in the staging directory", len(entries))
+	}
+}
+
+func TestImport_NotABundle(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "b.tar.gz")
+	if err := os.WriteFile(name, []byte("not a bundle"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Import(name, t.TempDir(), t.TempDir(), archive.DefaultLimits); err == nil {
+		t.Fatal("Import() expected error")
+	}
+}
+
+func TestImport_Limits(t *testing.T) {
+	src := t.TempDir()
+	cacheBuild(t, src, "v0.153.0", "extended", true)
+
+	bundlePath := filepath.Join(t.TempDir(), "hugo-bundle.tar.gz")
+	if _, err := Export(bundlePath, src, repository.HostPlatform(), []string{"v0.153.0/extended"}, nil); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	dst := t.TempDir()
+	limits := archive.DefaultLimits
+	limits.MaxEntries = 2
+	_, err := Import(bundlePath, dst, filepath.Join(dst, "staging"), limits)
+	var le *archive.LimitError
+	if !errors.As(err, &le) || le.Limit != archive.LimitEntries {
+		t.Fatalf("Import(): want entry limit error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "v0.153.0")); !os.IsNotExist(err) {
+		t.Fatalf("Import() copied a build from a bundle that exceeded a limit")
+	}
+}
+
+// cacheBuild creates a cached build in cacheDirPath, with a manifest if
+// withManifest is true.
+func cacheBuild(t *testing.T, cacheDirPath, tag, edition string, withManifest bool) {
+	t.Helper()
+	dir := filepath.Join(cacheDirPath, tag, edition)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "hugo"), []byte("hugo "+tag), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "LICENSE"), []byte("license"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if !withManifest {
+		return
+	}
+	m, err := cache.NewManifest(dir, tag, edition)
+	if err != nil {
+		t.Fatalf("NewManifest() error: %v", err)
+	}
+	if err := m.Write(dir); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+}
@@ -30,6 +30,10 @@ import (
 // SchemaVersion is the current cache directory schema version.
 const SchemaVersion = 1
 
+// PlatformsDirName is the name of the directory, within the cache directory,
+// that contains builds for platforms other than the host.
+const PlatformsDirName = "platforms"
+
 // SchemaFileName is the name of the cache schema version file stored at the
 // root of the cache directory.
 const SchemaFileName = "schema.json"
@@ -91,12 +95,29 @@ func EnsureSchema(cacheDirPath, defaultDirName string) (int, error) {
 
 // ExecName returns the name of the executable file based on the OS.
 func ExecName() string {
-	if runtime.GOOS == "windows" {
+	return ExecNameFor(runtime.GOOS)
+}
+
+// ExecNameFor returns the name of the executable file on the operating system
+// goos.
+func ExecNameFor(goos string) string {
+	if goos == "windows" {
 		return "hugo.exe"
 	}
 	return "hugo"
 }
 
+// BuildDirPath returns the path of the cache directory for the given build.
+// Builds for the host platform are stored in <cacheDirPath>/<tag>/<edition>,
+// and builds for other platforms are stored in
+// <cacheDirPath>/platforms/<goos>-<goarch>/<tag>/<edition>.
+func BuildDirPath(cacheDirPath, goos, goarch, tag, edition string) string {
+	if goos == runtime.GOOS && goarch == runtime.GOARCH {
+		return filepath.Join(cacheDirPath, tag, edition)
+	}
+	return filepath.Join(cacheDirPath, PlatformsDirName, goos+"-"+goarch, tag, edition)
+}
+
 // Size returns the size of the cache directory, in bytes, excluding
 // the specified exclude directory.
 func Size(cachePath, excludeDir string) (int64, error) {
@@ -121,3 +142,21 @@ func Size(cachePath, excludeDir string) (int64, error) {
 
 	return size, nil
 }
+
+// CommitBuild moves the extracted build in dir to buildDirPath, replacing
+// any existing build. dir and buildDirPath must be on the same file system.
+func CommitBuild(dir, buildDirPath string) error {
+	err := os.Chmod(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(buildDirPath)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(buildDirPath), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.Rename(dir, buildDirPath)
+}
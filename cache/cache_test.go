@@ -212,3 +212,30 @@ func TestEnsureSchema_UnreadableSchemaFile(t *testing.T) {
 		t.Fatal("EnsureSchema(): expected error for unreadable schema file")
 	}
 }
+
+func TestBuildDirPath(t *testing.T) {
+	base := filepath.Join("tmp", "cache")
+
+	got := BuildDirPath(base, runtime.GOOS, runtime.GOARCH, "v0.153.0", "extended")
+	if want := filepath.Join(base, "v0.153.0", "extended"); got != want {
+		t.Errorf("BuildDirPath() host: want %q got %q", want, got)
+	}
+
+	goos := "windows"
+	if runtime.GOOS == goos {
+		goos = "linux"
+	}
+	got = BuildDirPath(base, goos, "arm64", "v0.153.0", "extended")
+	if want := filepath.Join(base, PlatformsDirName, goos+"-arm64", "v0.153.0", "extended"); got != want {
+		t.Errorf("BuildDirPath() other: want %q got %q", want, got)
+	}
+}
+
+func TestExecNameFor(t *testing.T) {
+	if got := ExecNameFor("windows"); got != "hugo.exe" {
+		t.Errorf("ExecNameFor(windows): want hugo.exe got %q", got)
+	}
+	if got := ExecNameFor("darwin"); got != "hugo" {
+		t.Errorf("ExecNameFor(darwin): want hugo got %q", got)
+	}
+}
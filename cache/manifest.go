@@ -0,0 +1,135 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/jmooring/hvm/helpers"
+)
+
+// ManifestFileName is the name of the manifest file stored in each cached
+// build directory.
+const ManifestFileName = "hvm-manifest.json"
+
+// A Manifest records the provenance and content of a cached build.
+type Manifest struct {
+	Tag           string            `json:"tag"`                     // Release tag (e.g. v0.153.0)
+	Edition       string            `json:"edition"`                 // Edition (e.g. extended)
+	OS            string            `json:"os,omitempty"`            // Operating system of the build (e.g. linux)
+	Arch          string            `json:"arch,omitempty"`          // Architecture of the build (e.g. amd64)
+	ArchiveURL    string            `json:"archiveURL,omitempty"`    // URL of the release archive
+	ArchiveSHA256 string            `json:"archiveSHA256,omitempty"` // SHA-256 hex digest of the release archive
+	Verified      bool              `json:"verified"`                // Whether the archive digest was verified against a published checksum
+	HugoVersion   string            `json:"hugoVersion,omitempty"`   // First line of the output of the executable's version command, if it was run after caching
+	AdoptedFrom   string            `json:"adoptedFrom,omitempty"`   // Path of the executable copied to the cache by the adopt command, instead of a release archive
+	Files         map[string]string `json:"files"`                   // SHA-256 hex digest of each file, keyed by slash-separated relative path
+	Created       time.Time         `json:"created"`                 // When the build was cached
+}
+
+// NewManifest creates a new Manifest for the build in dir, recording the
+// digest of each file in dir.
+func NewManifest(dir, tag, edition string) (*Manifest, error) {
+	files, err := HashFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{
+		Tag:     tag,
+		Edition: edition,
+		Files:   files,
+		Created: time.Now().UTC(),
+	}, nil
+}
+
+// ReadManifest reads the manifest from the build directory dir.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest in %s: %w", dir, err)
+	}
+	return &m, nil
+}
+
+// Write writes the manifest to the build directory dir.
+func (m *Manifest) Write(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFileName), data, 0o644)
+}
+
+// Verify reports an error if the files in the build directory dir differ
+// from those recorded in the manifest, including missing or extra files.
+func (m *Manifest) Verify(dir string) error {
+	files, err := HashFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Files)) {
+		got, ok := files[name]
+		if !ok {
+			return fmt.Errorf("%s/%s: missing file %s", m.Tag, m.Edition, name)
+		}
+		if got != m.Files[name] {
+			return fmt.Errorf("%s/%s: digest mismatch for %s: got %s, expected %s", m.Tag, m.Edition, name, got, m.Files[name])
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if _, ok := m.Files[name]; !ok {
+			return fmt.Errorf("%s/%s: unexpected file %s", m.Tag, m.Edition, name)
+		}
+	}
+	return nil
+}
+
+// HashFiles returns the SHA-256 hex digest of each regular file in dir,
+// keyed by slash-separated path relative to dir. The manifest file is
+// excluded.
+func HashFiles(dir string) (map[string]string, error) {
+	files := map[string]string{}
+	err := fs.WalkDir(os.DirFS(dir), ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || path == ManifestFileName {
+			return nil
+		}
+		digest, err := helpers.FileDigest(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			return err
+		}
+		files[path] = digest
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	write(t, filepath.Join(dir, "hugo"), 20)
+	write(t, filepath.Join(dir, "docs", "LICENSE"), 5)
+
+	m, err := NewManifest(dir, "v0.153.0", "extended")
+	if err != nil {
+		t.Fatalf("NewManifest() error: %v", err)
+	}
+	if len(m.Files) != 2 || m.Files["docs/LICENSE"] == "" {
+		t.Fatalf("NewManifest(): unexpected files %v", m.Files)
+	}
+	m.Verified = true
+	if err := m.Write(dir); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	got, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("ReadManifest() error: %v", err)
+	}
+	if got.Tag != "v0.153.0" || got.Edition != "extended" || !got.Verified {
+		t.Fatalf("ReadManifest(): unexpected manifest %+v", got)
+	}
+
+	// The manifest file itself is not recorded.
+	if err := got.Verify(dir); err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+}
+
+func TestManifest_Verify(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(dir string) error
+	}{
+		{"modified", func(dir string) error { return os.WriteFile(filepath.Join(dir, "hugo"), []byte("tampered"), 0o644) }},
+		{"missing", func(dir string) error { return os.Remove(filepath.Join(dir, "hugo")) }},
+		{"extra", func(dir string) error { return os.WriteFile(filepath.Join(dir, "extra"), nil, 0o644) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			write(t, filepath.Join(dir, "hugo"), 20)
+			m, err := NewManifest(dir, "v0.153.0", "extended")
+			if err != nil {
+				t.Fatalf("NewManifest() error: %v", err)
+			}
+			if err := tt.modify(dir); err != nil {
+				t.Fatalf("modify: %v", err)
+			}
+			if err := m.Verify(dir); err == nil {
+				t.Fatal("Verify() expected error")
+			}
+		})
+	}
+}
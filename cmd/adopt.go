@@ -0,0 +1,193 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jmooring/hvm/cache"
+	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+)
+
+// adoptCmd represents the adopt command.
+var adoptCmd = &cobra.Command{
+	Use:   "adopt [path] | [flags]",
+	Short: "Add a Hugo executable installed by other means to the cache",
+	Long: `Add a Hugo executable installed by other means, such as a package manager, to
+the cache. If you do not specify the path of the executable, the hugo executable
+found in your PATH environment variable is adopted.
+
+The version/edition is determined by running the executable with the version
+command. The executable is copied to the cache, with a manifest recording that
+it was adopted, and was not verified against a published checksum. You can
+then use the version/edition without downloading it.
+
+Use the --install flag to also install the version/edition to use when version
+management is disabled for the current directory.
+
+  ` + app.Name + ` adopt
+  ` + app.Name + ` adopt /opt/homebrew/bin/hugo --install
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := ""
+		if len(args) > 0 {
+			path = args[0]
+		}
+		installFlag, err := cmd.Flags().GetBool("install")
+		cobra.CheckErr(err)
+		err = adopt(path, installFlag)
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the adopt command with the root command.
+func init() {
+	rootCmd.AddCommand(adoptCmd)
+	adoptCmd.Flags().Bool("install", false, "Also install the version/edition to use when version\nmanagement is disabled")
+}
+
+// adopt copies the Hugo executable at path, or found in PATH if path is
+// empty, to the cache, and installs it as the default if installFlag is true.
+func adopt(path string, installFlag bool) error {
+	execName := cache.ExecName()
+
+	if path == "" {
+		var err error
+		path, err = lookPathOutsideCache(execName)
+		if err != nil {
+			return err
+		}
+	} else if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		path = filepath.Join(path, execName)
+	}
+
+	// Adopt the executable itself, not a symbolic link to it.
+	src, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	src, err = filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(filepath.Base(src), execName) {
+		return fmt.Errorf("%s resolves to %s, which is not a %s executable: specify the path of the executable", path, src, execName)
+	}
+
+	bi, line, err := runVersion(src)
+	if err != nil {
+		return fmt.Errorf("unable to adopt %s: %w", src, err)
+	}
+	if strings.Contains(line, "-DEV") {
+		return fmt.Errorf("unable to adopt %s: it is a development build, which does not correspond to a release: %s", src, line)
+	}
+	if bi.Platform != repository.HostPlatform() {
+		return fmt.Errorf("unable to adopt %s: it was built for %s, not %s", src, bi.Platform, repository.HostPlatform())
+	}
+
+	asset := newAsset(bi.Platform)
+	asset.Tag = bi.Tag
+	asset.Edition = bi.Edition
+
+	exists, err := helpers.Exists(asset.DirPath(app.CacheDirPath))
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		fmt.Printf("%s/%s is already cached.\n", asset.Tag, asset.Edition)
+	} else {
+		err := adoptExecutable(asset, src, line)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Adopted %s/%s from %s.\n", asset.Tag, asset.Edition, src)
+	}
+
+	if installFlag {
+		return installDefault(asset)
+	}
+
+	return nil
+}
+
+// adoptExecutable copies the executable at src, whose version command reported
+// line, to the cache directory of asset with a manifest recording that it was
+// adopted. The copy is run with the version command before it is cached.
+func adoptExecutable(asset *repository.Asset, src, line string) error {
+	err := os.MkdirAll(app.StagingDirPath, 0o755)
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp(app.StagingDirPath, "")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	err = helpers.CopyFile(src, filepath.Join(dir, asset.ExecName))
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(filepath.Join(dir, asset.ExecName), 0o755)
+	if err != nil {
+		return err
+	}
+
+	// Some package managers install executables that do not run outside of
+	// the directory they were installed to.
+	if _, err := smokeTest(asset, dir); err != nil {
+		return fmt.Errorf("unable to adopt %s: the copy does not run: %w", src, err)
+	}
+
+	m, err := cache.NewManifest(dir, asset.Tag, asset.Edition)
+	if err != nil {
+		return err
+	}
+	m.OS = asset.Platform.OS
+	m.Arch = asset.Platform.Arch
+	m.HugoVersion = line
+	m.AdoptedFrom = src
+	err = m.Write(dir)
+	if err != nil {
+		return err
+	}
+
+	return cache.CommitBuild(dir, asset.DirPath(app.CacheDirPath))
+}
+
+// lookPathOutsideCache searches for the named executable in the directories
+// of the PATH environment variable, ignoring the "default" cache directory.
+func lookPathOutsideCache(name string) (string, error) {
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || filepath.Clean(dir) == filepath.Clean(app.DefaultDirPath) {
+			continue
+		}
+		path, err := exec.LookPath(filepath.Join(dir, name))
+		if err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("unable to find the %s executable in PATH: specify its path", name)
+}
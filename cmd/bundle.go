@@ -0,0 +1,41 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// bundleCmd represents the bundle command.
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Export and import bundles of cached versions",
+	Long: `Export and import bundles of cached versions.
+
+A bundle is a single file containing one or more cached versions/editions of
+the Hugo executable, a manifest for each, and the list of release tags. Export
+a bundle on a machine with network access, copy it to a machine without network
+access, then import it to populate the cache.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// init registers the bundle command with the root command.
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+}
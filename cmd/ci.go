@@ -0,0 +1,164 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+)
+
+//go:embed ci
+var ciTemplates embed.FS
+
+// ciTargets maps each CI/CD target to the embedded template for its
+// configuration file.
+var ciTargets = map[string]string{
+	"cloudflare-pages": "ci/cloudflare-pages.sh",
+	"github-actions":   "ci/github-actions.yml",
+	"gitlab":           "ci/gitlab.yml",
+	"netlify":          "ci/netlify.toml",
+	"vercel":           "ci/vercel.json",
+}
+
+// ciPlatform is the platform of the build images used by the CI/CD targets.
+var ciPlatform = repository.Platform{OS: "linux", Arch: "amd64"}
+
+// ciCmd represents the ci command.
+var ciCmd = &cobra.Command{
+	Use:   "ci --target <target>",
+	Short: "Generate a CI/CD configuration from the dot file",
+	Long: `Generate a configuration file, for the specified CI/CD target, that installs
+the Hugo version/edition in the ` + app.DotFileName + ` file of the current directory, or
+in one of the versionFiles, and builds the site. The target must be one of ` + ciTargetList() + `.
+
+The installation downloads the release asset for linux/amd64 from GitHub and
+verifies it against the published checksums file. The output is written to
+stdout. Except for the vercel target, whose output is saved as vercel.json, it
+begins with a comment describing where to save it.
+
+  ` + app.Name + ` gen ci --target github-actions > .github/workflows/hugo.yml
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		target, err := cmd.Flags().GetString("target")
+		cobra.CheckErr(err)
+		err = genCI(os.Stdout, target)
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the ci command with the gen command.
+func init() {
+	genCmd.AddCommand(ciCmd)
+	ciCmd.Flags().String("target", "", "CI/CD target: "+strings.Join(ciTargetNames(), ", "))
+	ciCmd.MarkFlagRequired("target")
+}
+
+// ciData is the data passed to the CI/CD configuration templates.
+type ciData struct {
+	BuildID string   // Build identifier from the dot file (e.g. v0.160.0/extended)
+	Install []string // Shell commands that install the executable to the current directory
+	Command string   // Single shell command that installs the executable to /tmp/hugo and builds the site
+}
+
+// genCI writes the configuration file for target to w, installing the
+// version/edition in the dot file of the current directory.
+func genCI(w io.Writer, target string) error {
+	name, ok := ciTargets[target]
+	if !ok {
+		return fmt.Errorf("target %q is not supported, must be one of %s", target, ciTargetList())
+	}
+
+	tag, edition, err := readDotFile(newDotFileManager())
+	if err != nil {
+		return err
+	}
+
+	data, err := newCIData(tag, edition)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New(path.Base(name)).
+		Funcs(template.FuncMap{"json": toJSON}).
+		ParseFS(ciTemplates, name)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+// newCIData returns the template data for tag and edition.
+func newCIData(tag, edition string) (ciData, error) {
+	archive, ok := repository.AssetName(tag, edition, ciPlatform)
+	if !ok {
+		return ciData{}, fmt.Errorf("%s/%s does not publish a release asset for %s", tag, edition, ciPlatform)
+	}
+	checksums := repository.ChecksumsName(tag, edition)
+	baseURL := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/",
+		app.ManagedApp.RepositoryOwner, app.ManagedApp.RepositoryName, tag)
+
+	install := []string{
+		fmt.Sprintf("curl -fsSLO %s%s", baseURL, archive),
+		fmt.Sprintf("curl -fsSL %s%s | grep ' %s$' | sha256sum -c", baseURL, checksums, archive),
+		fmt.Sprintf("tar -xzf %s hugo", archive),
+	}
+
+	return ciData{
+		BuildID: tag + "/" + edition,
+		Install: install,
+		Command: "mkdir -p /tmp/hugo && (cd /tmp/hugo && " + strings.Join(install, " && ") + ") && /tmp/hugo/hugo --gc --minify",
+	}, nil
+}
+
+// ciTargetNames returns the names of the CI/CD targets in sorted order.
+func ciTargetNames() []string {
+	names := make([]string, 0, len(ciTargets))
+	for name := range ciTargets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ciTargetList returns the names of the CI/CD targets as a human-readable list.
+func ciTargetList() string {
+	list, _ := helpers.JoinWithConjunction(ciTargetNames(), "or")
+	return list
+}
+
+// toJSON returns v encoded as JSON without escaping HTML characters. A JSON
+// string is also a valid TOML basic string.
+func toJSON(v any) (string, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
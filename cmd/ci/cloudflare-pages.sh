@@ -0,0 +1,15 @@
+#!/usr/bin/env bash
+# Generated by "hvm gen ci" from the .hvm file: Hugo {{ .BuildID }}.
+# Save as build.sh. In the build settings of your Cloudflare Pages project, set
+# the build command to "bash build.sh" and the build output directory to
+# "public".
+set -euo pipefail
+
+mkdir -p /tmp/hugo
+(
+  cd /tmp/hugo
+{{- range .Install }}
+  {{ . }}
+{{- end }}
+)
+/tmp/hugo/hugo --gc --minify
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenCI(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	saved := app.DotFilePath
+	app.DotFilePath = filepath.Join(dir, app.DotFileName)
+	t.Cleanup(func() { app.DotFilePath = saved })
+
+	if err := os.WriteFile(app.DotFilePath, []byte("v0.153.0/withdeploy"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range ciTargetNames() {
+		t.Run(target, func(t *testing.T) {
+			var b strings.Builder
+			if err := genCI(&b, target); err != nil {
+				t.Fatalf("genCI: %v", err)
+			}
+			got := b.String()
+			if !strings.Contains(got, "hugo_withdeploy_0.153.0_linux-amd64.tar.gz") {
+				t.Errorf("output does not install the withdeploy edition:\n%s", got)
+			}
+			if target == "vercel" && !json.Valid([]byte(got)) {
+				t.Errorf("output is not valid JSON:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestGenCI_PerEditionChecksums(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	saved := app.DotFilePath
+	app.DotFilePath = filepath.Join(dir, app.DotFileName)
+	t.Cleanup(func() { app.DotFilePath = saved })
+
+	if err := os.WriteFile(app.DotFilePath, []byte("v0.55.0/extended"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := genCI(&b, "gitlab"); err != nil {
+		t.Fatalf("genCI: %v", err)
+	}
+	got := b.String()
+	if !strings.Contains(got, "/v0.55.0/hugo_extended_0.55.0_checksums.txt | grep ' hugo_extended_0.55.0_Linux-64bit.tar.gz$' | sha256sum -c") {
+		t.Errorf("output does not verify against the per-edition checksums file:\n%s", got)
+	}
+}
@@ -19,25 +19,37 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 
+	"github.com/jmooring/hvm/archive"
 	"github.com/jmooring/hvm/cache"
+	"github.com/jmooring/hvm/dotfile"
 	gh "github.com/jmooring/hvm/github"
 	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/libc"
+	"github.com/jmooring/hvm/links"
+	"github.com/jmooring/hvm/mirror"
 	"github.com/jmooring/hvm/repository"
 	"github.com/jmooring/hvm/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/mod/semver"
 )
 
 // An application contains details about the application. Some are constants, while
 // others depend on the user environment.
 type application struct {
+	AliasesFileName string     // Name of the registry of aliases within the application configuration directory
+	AliasesFilePath string     // Path to the registry of aliases
+	ArchivesDirName string     // Name of the directory of retained release archives within the application cache directory
+	ArchivesDirPath string     // Path to the directory of retained release archives
 	CacheDirPath    string     // Path to the application cache directory
 	ConfigDirPath   string     // Path to the application configuration directory
 	ConfigFilePath  string     // Path to the application configuration file
@@ -45,10 +57,16 @@ type application struct {
 	DefaultDirPath  string     // Path to the "default" directory within the application cache directory
 	DotFileName     string     // Name of the dot file written to the current directory (e.g., .hvm)
 	DotFilePath     string     // Path to the dot file
+	HistoryFileName string     // Name of the history of dot files within the application configuration directory
+	HistoryFilePath string     // Path to the history of dot files
+	LinksFileName   string     // Name of the registry of linked builds within the application configuration directory
+	LinksFilePath   string     // Path to the registry of linked builds
 	ManagedApp      managedApp // Details about the application being managed
 	Name            string     // Name of the application
 	RepositoryName  string     // Name of the GitHub repository
 	RepositoryOwner string     // Owner of the GitHub repository
+	StagingDirName  string     // Name of the directory of in-progress downloads within the application cache directory
+	StagingDirPath  string     // Path to the directory of in-progress downloads
 	UpdateURL       string     // URL to update the application
 	WorkingDir      string     // Current working directory
 }
@@ -63,11 +81,20 @@ type managedApp struct {
 // A configuration contains the current configuration parameters from environment
 // variables, the configuration file, or default values, in that order.
 type configuration struct {
-	DefaultEdition   string `mapstructure:"defaultEdition"   toml:"defaultEdition"`   // Default edition of the hugo executable to "use" or "install"
-	GitHubToken      string `mapstructure:"githubToken"      toml:"githubToken"`      // A GitHub personal access token
-	NumTagsToDisplay int    `mapstructure:"numTagsToDisplay" toml:"numTagsToDisplay"` // Number of tags to display when using the "use" and "install" commands
-	PromptForEdition bool   `mapstructure:"promptForEdition" toml:"promptForEdition"` // Whether to prompt the user to select an edition when using the "use" or "install" commands
-	SortAscending    bool   `mapstructure:"sortAscending"    toml:"sortAscending"`    // Whether to display the tags in ascending order
+	DefaultEdition    string        `mapstructure:"defaultEdition"    toml:"defaultEdition"`    // Default edition of the hugo executable to "use" or "install"
+	ExtractMaxBytes   int64         `mapstructure:"extractMaxBytes"   toml:"extractMaxBytes"`   // Maximum number of bytes extracted from an archive
+	ExtractMaxEntries int64         `mapstructure:"extractMaxEntries" toml:"extractMaxEntries"` // Maximum number of entries extracted from an archive
+	ExtractMaxRatio   int64         `mapstructure:"extractMaxRatio"   toml:"extractMaxRatio"`   // Maximum ratio of the number of bytes extracted from an archive to its size
+	GitHubToken       string        `mapstructure:"githubToken"       toml:"githubToken"`       // A GitHub personal access token
+	KeepArchives      bool          `mapstructure:"keepArchives"      toml:"keepArchives"`      // Whether to retain verified release archives in the cache for the "serve" command
+	Mirrors           []mirror.Rule `mapstructure:"mirrors"           toml:"mirrors"`           // Ordered list of mirror rules to try before downloading from the origin
+	NumTagsToDisplay  int           `mapstructure:"numTagsToDisplay"  toml:"numTagsToDisplay"`  // Number of tags to display when using the "use" and "install" commands
+	Peers             []string      `mapstructure:"peers"             toml:"peers"`             // Base URLs of "hvm serve" instances to try before mirrors and the origin
+	PromptForEdition  bool          `mapstructure:"promptForEdition"  toml:"promptForEdition"`  // Whether to prompt the user to select an edition when using the "use" or "install" commands
+	ReleaseSource     string        `mapstructure:"releaseSource"     toml:"releaseSource"`     // URL of a release index, or path of a directory of release archives, to use instead of the GitHub API
+	SmokeTest         bool          `mapstructure:"smokeTest"         toml:"smokeTest"`         // Whether to run each newly cached executable with the "version" command
+	SortAscending     bool          `mapstructure:"sortAscending"     toml:"sortAscending"`     // Whether to display the tags in ascending order
+	VersionFiles      []string      `mapstructure:"versionFiles"      toml:"versionFiles"`      // Ordered list of files written by other tools to read the version from when the dot file does not exist
 }
 
 // orderedAvailableEditions returns the subset of repository.ValidEditions that are
@@ -83,9 +110,10 @@ func orderedAvailableEditions(editions map[string]string) []string {
 }
 
 // resolveEdition resolves the edition for asset from an explicit value, an
-// interactive prompt, or the configured default. It returns true if the user
-// cancelled the prompt.
-func resolveEdition(asset *repository.Asset, editions map[string]string, explicitEdition, promptMsg string) (bool, error) {
+// interactive prompt, or the configured default, and checks that the edition
+// can run on the host with checkLibc. The prompt is written to w. It returns
+// true if the user cancelled the prompt.
+func resolveEdition(w io.Writer, asset *repository.Asset, editions map[string]string, explicitEdition, promptMsg string) (bool, error) {
 	if explicitEdition != "" {
 		if !slices.Contains(repository.ValidEditions, explicitEdition) {
 			s, err := helpers.JoinWithConjunction(repository.ValidEditions, "or")
@@ -99,7 +127,7 @@ func resolveEdition(asset *repository.Asset, editions map[string]string, explici
 		}
 		asset.Edition = explicitEdition
 	} else if config.PromptForEdition {
-		err := repository.SelectEdition(asset, promptMsg, orderedAvailableEditions(editions))
+		err := repository.SelectEdition(w, asset, promptMsg, orderedAvailableEditions(editions))
 		if err != nil {
 			return false, err
 		}
@@ -112,7 +140,35 @@ func resolveEdition(asset *repository.Asset, editions map[string]string, explici
 		}
 		asset.Edition = config.DefaultEdition
 	}
-	return false, nil
+	return false, checkLibc(asset)
+}
+
+// detectLibc returns the C standard library of the host. Tests replace it to
+// simulate other hosts.
+var detectLibc = libc.Detect
+
+// checkLibc checks that the edition of asset can run on a Linux host. Editions
+// other than standard are dynamically linked against glibc. checkLibc returns
+// an error if the host uses musl without a glibc compatibility layer, and
+// prints a warning if it uses musl with one. Assets for other platforms are
+// not checked. The glibc version is not checked, because the minimum version
+// required by each release is not published; an executable that requires a
+// newer glibc fails the smoke test instead.
+func checkLibc(asset *repository.Asset) error {
+	p := asset.TargetPlatform()
+	if asset.Edition == "standard" || p.OS != "linux" || p != repository.HostPlatform() {
+		return nil
+	}
+
+	info := detectLibc()
+	if info.Family != libc.Musl {
+		return nil
+	}
+	if !info.RunsGlibc() {
+		return fmt.Errorf("edition %q requires glibc, but this system uses %s: use the standard edition, or install a glibc compatibility layer such as gcompat", asset.Edition, info)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: edition %q requires glibc, but this system uses %s, which may not be able to run it; the standard edition does not require glibc\n", asset.Edition, info)
+	return nil
 }
 
 // promptYesNo prints question with a (Y/n) or (y/N) suffix and loops until
@@ -152,22 +208,28 @@ func promptYesNo(question string, defaultYes bool) bool {
 	}
 }
 
-// resolveAsset resolves the asset for the given version string, tag prompt
-// message, and edition prompt message. It returns nil if the user cancelled
-// an interactive prompt. version may be empty (triggers interactive tag
-// selection), a bare tag ("v0.153.0"), or a tag/edition pair ("v0.153.0/extended").
-func resolveAsset(version, tagMsg, editionMsg string) (*repository.Asset, error) {
-	asset := repository.NewAsset(cache.ExecName())
+// resolveAsset resolves the asset for the given version string, target
+// platform, tag prompt message, and edition prompt message. It returns nil if
+// the user cancelled an interactive prompt. version may be empty (triggers
+// interactive tag selection), a bare tag ("v0.153.0"), or a tag/edition pair
+// ("v0.153.0/extended"). Prompts are written to w.
+func resolveAsset(w io.Writer, version string, p repository.Platform, tagMsg, editionMsg string) (*repository.Asset, error) {
+	// A cached version/edition does not require access to the release source.
+	asset, err := cachedAsset(version, p)
+	if err != nil || asset != nil {
+		return asset, err
+	}
+
+	asset = newAsset(p)
 
-	client := gh.NewClient(config.GitHubToken)
-	repo, err := repository.NewRepository(app.ManagedApp.RepositoryOwner, app.ManagedApp.RepositoryName, client, app.CacheDirPath)
+	repo, err := newRepository()
 	if err != nil {
 		return nil, err
 	}
 
 	var explicitEdition string
 	if version == "" {
-		err := repo.SelectTag(asset, tagMsg, config.SortAscending, config.NumTagsToDisplay)
+		err := repo.SelectTag(w, asset, tagMsg, config.SortAscending, config.NumTagsToDisplay)
 		if err != nil {
 			return nil, err
 		}
@@ -194,7 +256,7 @@ func resolveAsset(version, tagMsg, editionMsg string) (*repository.Asset, error)
 		return nil, err
 	}
 
-	cancelled, err := resolveEdition(asset, editions, explicitEdition, editionMsg)
+	cancelled, err := resolveEdition(w, asset, editions, explicitEdition, editionMsg)
 	if err != nil {
 		return nil, err
 	}
@@ -209,9 +271,135 @@ func resolveAsset(version, tagMsg, editionMsg string) (*repository.Asset, error)
 	return asset, nil
 }
 
+// cachedAsset returns the asset for version if version is a tag/edition pair
+// (e.g. "v0.153.0/extended" or "0.153.0/extended") that is already cached for
+// platform p, otherwise nil.
+func cachedAsset(version string, p repository.Platform) (*repository.Asset, error) {
+	tag, edition, ok := strings.Cut(version, "/")
+	if !ok || !slices.Contains(repository.ValidEditions, edition) {
+		return nil, nil
+	}
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	if !semver.IsValid(tag) {
+		return nil, nil // e.g. "latest"
+	}
+
+	asset := newAsset(p)
+	asset.Tag = tag
+	asset.Edition = edition
+
+	exists, err := helpers.Exists(asset.DirPath(app.CacheDirPath))
+	if err != nil || !exists {
+		return nil, err
+	}
+	return asset, nil
+}
+
+// newDotFileManager returns a manager for the dot file of the current
+// directory that falls back to the configured version files.
+func newDotFileManager() *dotfile.Manager {
+	return dotfile.NewManager(app.DotFilePath, app.DotFileName, app.Name, config.DefaultEdition, config.VersionFiles, app.HistoryFilePath)
+}
+
+// readDotFile returns the tag and edition read by dm, or an error if the
+// current directory does not contain a dot file or, if dm has fallbacks, a
+// version file that pins Hugo.
+func readDotFile(dm *dotfile.Manager) (tag, edition string, err error) {
+	buildID, err := dm.Read()
+	if err != nil {
+		return "", "", err
+	}
+	if buildID == "" {
+		return "", "", fmt.Errorf("the current directory does not contain an %s file: run \"%s use\" to select a version", app.DotFileName, app.Name)
+	}
+	buildID, err = resolveAlias(buildID)
+	if err != nil {
+		return "", "", err
+	}
+	if _, ok := links.ParseBuildID(buildID); ok {
+		return "", "", fmt.Errorf("%s is a linked build, which does not correspond to a release: run \"%s use\" to select a version", buildID, app.Name)
+	}
+	tag, edition, _ = strings.Cut(buildID, "/")
+	return tag, edition, nil
+}
+
+// newAsset returns a new asset for platform p.
+func newAsset(p repository.Platform) *repository.Asset {
+	asset := repository.NewAsset(cache.ExecNameFor(p.OS))
+	asset.Platform = p
+	return asset
+}
+
+// addPlatformFlags adds the --os and --arch flags to cmd.
+func addPlatformFlags(cmd *cobra.Command) {
+	cmd.Flags().String("os", "", "Target operating system (e.g. windows); the default\nis the operating system of this machine")
+	cmd.Flags().String("arch", "", "Target architecture (e.g. arm64); the default is the\narchitecture of this machine")
+}
+
+// platformFromFlags returns the target platform specified by the --os and
+// --arch flags.
+func platformFromFlags(cmd *cobra.Command) (repository.Platform, error) {
+	goos, err := cmd.Flags().GetString("os")
+	if err != nil {
+		return repository.Platform{}, err
+	}
+	goarch, err := cmd.Flags().GetString("arch")
+	if err != nil {
+		return repository.Platform{}, err
+	}
+	return repository.ParsePlatform(goos, goarch)
+}
+
+// newRepository returns a repository backed by the configured release source:
+// a local directory of release archives or a release index if
+// config.ReleaseSource is set, otherwise the GitHub API.
+func newRepository() (*repository.Repository, error) {
+	if config.ReleaseSource == "" {
+		client := gh.NewClient(config.GitHubToken)
+		return repository.NewRepository(app.ManagedApp.RepositoryOwner, app.ManagedApp.RepositoryName, client, app.CacheDirPath)
+	}
+
+	dir, ok := localPath(config.ReleaseSource)
+	if !ok {
+		return repository.NewIndexRepository(config.ReleaseSource, newHTTPClient())
+	}
+
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("release source: %w", err)
+	}
+	if !fi.IsDir() {
+		// A local release index.
+		return repository.NewIndexRepository(helpers.PathToFileURL(dir), newHTTPClient())
+	}
+	return repository.NewDirRepository(dir)
+}
+
+// localPath returns the local file system path for source, which is either a
+// file URL or a path, and false if source is an http or https URL.
+func localPath(source string) (string, bool) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return source, true
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return "", false
+	case "file":
+		return helpers.FileURLToPath(u), true
+	}
+	return source, true // a path, possibly beginning with a Windows drive letter
+}
+
 var app application = application{
-	DefaultDirName: "default",
-	DotFileName:    ".hvm",
+	AliasesFileName: "aliases.json",
+	ArchivesDirName: "archives",
+	DefaultDirName:  "default",
+	DotFileName:     ".hvm",
+	HistoryFileName: "history.json",
+	LinksFileName:   "links.json",
 	ManagedApp: managedApp{
 		RepositoryName:  "hugo",
 		RepositoryOwner: "gohugoio",
@@ -219,6 +407,7 @@ var app application = application{
 	Name:            "hvm",
 	RepositoryName:  "hvm",
 	RepositoryOwner: "jmooring",
+	StagingDirName:  "staging",
 	UpdateURL:       "https://github.com/jmooring/hvm/releases/latest",
 }
 
@@ -260,10 +449,17 @@ func init() {
 func initConfig() {
 	// Set default values.
 	viper.SetDefault("defaultEdition", "standard")
+	viper.SetDefault("extractMaxBytes", archive.DefaultLimits.MaxTotalBytes)
+	viper.SetDefault("extractMaxEntries", archive.DefaultLimits.MaxEntries)
+	viper.SetDefault("extractMaxRatio", archive.DefaultLimits.MaxRatio)
 	viper.SetDefault("githubToken", "")
+	viper.SetDefault("keepArchives", false)
 	viper.SetDefault("numTagsToDisplay", 32)
 	viper.SetDefault("promptForEdition", true)
+	viper.SetDefault("releaseSource", "")
+	viper.SetDefault("smokeTest", true)
 	viper.SetDefault("sortAscending", false)
+	viper.SetDefault("versionFiles", dotfile.DefaultFallbacks)
 
 	// Create config directory.
 	userConfigDir, err := os.UserConfigDir()
@@ -302,6 +498,13 @@ func initConfig() {
 		cobra.CheckErr(err)
 	}
 
+	for _, k := range []string{"extractMaxBytes", "extractMaxEntries", "extractMaxRatio"} {
+		if viper.GetInt64(k) <= 0 {
+			err = fmt.Errorf("configuration: %s must be a positive integer: see %s", k, viper.ConfigFileUsed())
+			cobra.CheckErr(err)
+		}
+	}
+
 	k = "githubToken"
 	if !helpers.IsString(viper.Get(k)) {
 		err = fmt.Errorf("configuration: %s must be a string: see %s", k, viper.ConfigFileUsed())
@@ -314,6 +517,12 @@ func initConfig() {
 		cobra.CheckErr(err)
 	}
 
+	k = "releaseSource"
+	if !helpers.IsString(viper.Get(k)) {
+		err = fmt.Errorf("configuration: %s must be a string: see %s", k, viper.ConfigFileUsed())
+		cobra.CheckErr(err)
+	}
+
 	// Validate the defaultEdition value.
 	defaultEdition := viper.GetString("defaultEdition")
 	if !slices.Contains(repository.ValidEditions, defaultEdition) {
@@ -326,6 +535,42 @@ func initConfig() {
 	// Unmarshal the config to the Config struct.
 	err = viper.Unmarshal(&config)
 	cobra.CheckErr(err)
+
+	// Validate the releaseSource value.
+	if rs := viper.GetString("releaseSource"); rs != "" {
+		u, err := url.Parse(rs)
+		if err == nil && len(u.Scheme) > 1 && !slices.Contains([]string{"http", "https", "file"}, u.Scheme) {
+			err = fmt.Errorf("configuration: %s %q is invalid, must be an http, https, or file URL, or a path: see %s", "releaseSource", rs, viper.ConfigFileUsed())
+			cobra.CheckErr(err)
+		}
+	}
+
+	// Validate the peer URLs.
+	for i, p := range config.Peers {
+		u, err := url.Parse(p)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			err = fmt.Errorf("configuration: peers[%d] %q is invalid, must be an http or https URL: see %s", i, p, viper.ConfigFileUsed())
+			cobra.CheckErr(err)
+		}
+	}
+
+	// Validate the version files.
+	for i, name := range config.VersionFiles {
+		if !dotfile.IsFallback(name) {
+			s, err := helpers.JoinWithConjunction(dotfile.Fallbacks(), "or")
+			cobra.CheckErr(err)
+			err = fmt.Errorf("configuration: versionFiles[%d] %q is invalid, must be one of %s: see %s", i, name, s, viper.ConfigFileUsed())
+			cobra.CheckErr(err)
+		}
+	}
+
+	// Validate the mirror rules.
+	for i, r := range config.Mirrors {
+		if err := r.Validate(); err != nil {
+			err = fmt.Errorf("configuration: mirrors[%d]: %s: see %s", i, err, viper.ConfigFileUsed())
+			cobra.CheckErr(err)
+		}
+	}
 }
 
 // initApp initializes the application and creates the application cache
@@ -340,11 +585,16 @@ func initApp() {
 	wd, err := os.Getwd()
 	cobra.CheckErr(err)
 
+	app.AliasesFilePath = filepath.Join(userConfigDir, app.Name, app.AliasesFileName)
+	app.ArchivesDirPath = filepath.Join(userCacheDir, app.Name, app.ArchivesDirName)
 	app.CacheDirPath = filepath.Join(userCacheDir, app.Name)
 	app.ConfigDirPath = filepath.Join(userConfigDir, app.Name)
 	app.ConfigFilePath = viper.ConfigFileUsed()
 	app.DefaultDirPath = filepath.Join(userCacheDir, app.Name, app.DefaultDirName)
 	app.DotFilePath = filepath.Join(wd, app.DotFileName)
+	app.HistoryFilePath = filepath.Join(userConfigDir, app.Name, app.HistoryFileName)
+	app.LinksFilePath = filepath.Join(userConfigDir, app.Name, app.LinksFileName)
+	app.StagingDirPath = filepath.Join(userCacheDir, app.Name, app.StagingDirName)
 	app.WorkingDir = wd
 
 	err = os.MkdirAll(app.CacheDirPath, 0o755)
@@ -356,3 +606,36 @@ func initApp() {
 		fmt.Fprintf(os.Stderr, "Info: cache migrated to new format: %d old cached version(s) removed\n", n)
 	}
 }
+
+// extractLimits returns the limits for extracting archives, as configured by
+// the extractMaxBytes, extractMaxEntries, and extractMaxRatio configuration
+// values.
+func extractLimits() archive.Limits {
+	limits := archive.DefaultLimits
+	limits.MaxTotalBytes = config.ExtractMaxBytes
+	limits.MaxEntries = config.ExtractMaxEntries
+	limits.MaxRatio = config.ExtractMaxRatio
+	return limits
+}
+
+// limitErrorWithHint returns err, with the name of the configuration value
+// that controls the limit appended if err is an *archive.LimitError for a
+// configurable limit.
+func limitErrorWithHint(err error) error {
+	var le *archive.LimitError
+	if !errors.As(err, &le) {
+		return err
+	}
+	var k string
+	switch le.Limit {
+	case archive.LimitTotalBytes:
+		k = "extractMaxBytes"
+	case archive.LimitEntries:
+		k = "extractMaxEntries"
+	case archive.LimitRatio:
+		k = "extractMaxRatio"
+	default:
+		return err
+	}
+	return fmt.Errorf("%w: to raise the limit, set the %s configuration value: see %s", err, k, viper.ConfigFileUsed())
+}
@@ -17,12 +17,22 @@ limitations under the License.
 package cmd
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/jmooring/hvm/cache"
 	"github.com/rogpeppe/go-internal/testscript"
+	"golang.org/x/mod/semver"
 )
 
 // TestMain provides the entry point for the test binary, allowing testscript
@@ -43,6 +53,7 @@ func TestCommand(t *testing.T) {
 	testscript.Run(t, testscript.Params{
 		Dir:   "testscripts",
 		Setup: setup,
+		Cmds:  cmds,
 	})
 }
 
@@ -51,6 +62,7 @@ func TestCommandConfig(t *testing.T) {
 	testscript.Run(t, testscript.Params{
 		Dir:   "testscripts/config",
 		Setup: setup,
+		Cmds:  cmds,
 	})
 }
 
@@ -59,6 +71,7 @@ func TestCommandInstall(t *testing.T) {
 	testscript.Run(t, testscript.Params{
 		Dir:   "testscripts/install",
 		Setup: setup,
+		Cmds:  cmds,
 	})
 }
 
@@ -67,6 +80,7 @@ func TestCommandRemove(t *testing.T) {
 	testscript.Run(t, testscript.Params{
 		Dir:   "testscripts/remove",
 		Setup: setup,
+		Cmds:  cmds,
 	})
 }
 
@@ -75,6 +89,7 @@ func TestCommandStatus(t *testing.T) {
 	testscript.Run(t, testscript.Params{
 		Dir:   "testscripts/status",
 		Setup: setup,
+		Cmds:  cmds,
 	})
 }
 
@@ -83,6 +98,7 @@ func TestCommandUse(t *testing.T) {
 	testscript.Run(t, testscript.Params{
 		Dir:   "testscripts/use",
 		Setup: setup,
+		Cmds:  cmds,
 	})
 }
 
@@ -96,6 +112,8 @@ func setup(env *testscript.Env) error {
 		// User cache and config dirs: we use os.UserCacheDir and os.UserConfigDir
 		env.Setenv("LocalAppData", "cache")
 		env.Setenv("AppData", "config")
+		// The executables created by mkrelease do not run on Windows.
+		env.Setenv("HVM_SMOKETEST", "false")
 	case "linux":
 		// User cache and config dirs: we use os.UserCacheDir and os.UserConfigDir
 		env.Setenv("XDG_CACHE_HOME", env.Getenv("WORK")+"/cache")
@@ -105,3 +123,247 @@ func setup(env *testscript.Env) error {
 	}
 	return nil
 }
+
+// cmds contains custom testscript commands.
+var cmds = map[string]func(ts *testscript.TestScript, neg bool, args []string){
+	"mkexec":    mkexec,
+	"mkrelease": mkrelease,
+}
+
+// mkrelease creates a fake Hugo release archive for the current platform, or
+// for the platform specified by the -platform flag, for each of the specified
+// editions, and writes their digests to the release's checksums file. Each
+// archive contains a hugo executable, a LICENSE file, and a README.md file.
+// On systems other than Windows, the executable is a shell script whose
+// version command reports the release's tag, edition, and platform, or the
+// line specified by the -reports flag.
+//
+// Usage: mkrelease [-platform os/arch] [-reports line] dir tag edition...
+func mkrelease(ts *testscript.TestScript, neg bool, args []string) {
+	if neg {
+		ts.Fatalf("unsupported: ! mkrelease")
+	}
+	goos, goarch := runtime.GOOS, runtime.GOARCH
+	var reports string
+	for len(args) > 1 && strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "-platform":
+			var ok bool
+			goos, goarch, ok = strings.Cut(args[1], "/")
+			if !ok {
+				ts.Fatalf("invalid platform %q", args[1])
+			}
+		case "-reports":
+			reports = args[1]
+		default:
+			ts.Fatalf("unknown flag %q", args[0])
+		}
+		args = args[2:]
+	}
+	if len(args) < 3 {
+		ts.Fatalf("usage: mkrelease [-platform os/arch] [-reports line] dir tag edition...")
+	}
+	dir := ts.MkAbs(args[0])
+	tag := args[1]
+	version := strings.TrimPrefix(tag, "v")
+
+	var suffix string
+	switch goos {
+	case "darwin":
+		suffix = "_darwin-universal.tar.gz" // v0.103.0 through v0.152.x
+	case "windows":
+		suffix = "_windows-" + goarch + ".zip"
+	default:
+		suffix = "_linux-" + goarch + ".tar.gz"
+		if goarch == "arm64" && semver.Compare(tag, "v0.103.0") == -1 {
+			suffix = "_Linux-ARM64.deb"
+		}
+	}
+
+	ts.Check(os.MkdirAll(dir, 0o755))
+
+	var checksums strings.Builder
+	for _, edition := range args[2:] {
+		prefix := "hugo_"
+		if edition != "standard" {
+			prefix = "hugo_" + edition + "_"
+		}
+		name := prefix + version + suffix
+
+		files := map[string]string{
+			cache.ExecNameFor(goos): fakeExecutable(tag, edition, goos, goarch, reports),
+			"LICENSE":               "license\n",
+			"README.md":             "readme\n",
+		}
+
+		var err error
+		switch {
+		case strings.HasSuffix(name, ".zip"):
+			err = writeZip(filepath.Join(dir, name), files)
+		case strings.HasSuffix(name, ".deb"):
+			err = writeDeb(filepath.Join(dir, name), files)
+		default:
+			err = writeTarGZ(filepath.Join(dir, name), files)
+		}
+		ts.Check(err)
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		ts.Check(err)
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(&checksums, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+
+	// Append so that releases for several platforms share a checksums file.
+	f, err := os.OpenFile(filepath.Join(dir, "hugo_"+version+"_checksums.txt"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	ts.Check(err)
+	_, err = f.WriteString(checksums.String())
+	ts.Check(err)
+	ts.Check(f.Close())
+}
+
+// mkexec creates a fake Hugo executable for the current platform at path.
+// On systems other than Windows, the executable is a shell script whose
+// version command reports the tag and edition, or the line specified by the
+// -reports flag.
+//
+// Usage: mkexec [-reports line] path tag edition
+func mkexec(ts *testscript.TestScript, neg bool, args []string) {
+	if neg {
+		ts.Fatalf("unsupported: ! mkexec")
+	}
+	var reports string
+	if len(args) > 1 && args[0] == "-reports" {
+		reports = args[1]
+		args = args[2:]
+	}
+	if len(args) != 3 {
+		ts.Fatalf("usage: mkexec [-reports line] path tag edition")
+	}
+	path := ts.MkAbs(args[0])
+	ts.Check(os.MkdirAll(filepath.Dir(path), 0o755))
+	ts.Check(os.WriteFile(path, []byte(fakeExecutable(args[1], args[2], runtime.GOOS, runtime.GOARCH, reports)), 0o755))
+}
+
+// fakeExecutable returns the content of a fake Hugo executable whose version
+// command reports tag, edition, and platform goos/goarch, or reports if it is
+// not empty.
+func fakeExecutable(tag, edition, goos, goarch, reports string) string {
+	if reports == "" {
+		var buildTags string
+		if edition != "standard" {
+			buildTags = "+" + strings.ReplaceAll(edition, "_", "+")
+		}
+		reports = fmt.Sprintf("hugo %s-0000000%s %s/%s BuildDate=unknown", tag, buildTags, goos, goarch)
+	}
+	return "#!/bin/sh\necho '" + reports + "'\n"
+}
+
+// writeTarGZ writes a gzipped tarball containing files, keyed by name, to dst.
+func writeTarGZ(dst string, files map[string]string) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(tw, content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeDeb writes a Debian package to dst. The executable is installed to
+// usr/local/bin, and the other files, keyed by name, to usr/share/doc/hugo.
+func writeDeb(dst string, files map[string]string) error {
+	data := map[string]string{}
+	for name, content := range files {
+		if strings.HasPrefix(name, "hugo") {
+			data["./usr/local/bin/"+name] = content
+		} else {
+			data["./usr/share/doc/hugo/"+name] = content
+		}
+	}
+
+	tmp, err := os.MkdirTemp("", "")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	members := []string{"debian-binary", "control.tar.gz", "data.tar.gz"}
+	if err := os.WriteFile(filepath.Join(tmp, members[0]), []byte("2.0\n"), 0o644); err != nil {
+		return err
+	}
+	if err := writeTarGZ(filepath.Join(tmp, members[1]), map[string]string{"./control": "Package: hugo\n"}); err != nil {
+		return err
+	}
+	if err := writeTarGZ(filepath.Join(tmp, members[2]), data); err != nil {
+		return err
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.WriteString(f, "!<arch>\n"); err != nil {
+		return err
+	}
+	for _, name := range members {
+		content, err := os.ReadFile(filepath.Join(tmp, name))
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(f, "%-16s%-12d%-6d%-6d%-8o%-10d`\n", name+"/", 0, 0, 0, 0o644, len(content)); err != nil {
+			return err
+		}
+		if len(content)%2 == 1 {
+			content = append(content, '\n')
+		}
+		if _, err := f.Write(content); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// writeZip writes a zip file containing files, keyed by name, to dst.
+func writeZip(dst string, files map[string]string) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		fh := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		fh.SetMode(0o755)
+		w, err := zw.CreateHeader(fh)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, content); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
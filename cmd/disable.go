@@ -22,6 +22,7 @@ import (
 
 	"github.com/jmooring/hvm/helpers"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // disableCmd represents the disable command.
@@ -56,5 +57,11 @@ func disable() error {
 
 	fmt.Println("Version management has been disabled for the current directory.")
 
+	// A version file written by another tool still pins a version.
+	buildID, source, err := newDotFileManager().ReadWithSource()
+	if err == nil && source != "" {
+		fmt.Fprintf(os.Stderr, "Warning: the %s file in the current directory pins Hugo %s: to ignore it, remove it from the versionFiles configuration value: see %s\n", source, buildID, viper.ConfigFileUsed())
+	}
+
 	return nil
 }
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmooring/hvm/bundle"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the bundle export command.
+var exportCmd = &cobra.Command{
+	Use:   "export <version/edition>... [flags]",
+	Short: "Export cached versions to a bundle",
+	Long: `Export one or more cached versions/editions of the Hugo executable to a
+bundle file:
+
+  ` + app.Name + ` bundle export v0.153.0/extended v0.152.2/standard -o hugo-bundle.tar.gz
+
+Use the --os and --arch flags to export versions/editions fetched for another
+platform. A bundle contains versions/editions for a single platform.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := exportBundle(cmd, args)
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the export command with the bundle command.
+func init() {
+	bundleCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringP("output", "o", "hugo-bundle.tar.gz", "Write the bundle to this file")
+	addPlatformFlags(exportCmd)
+}
+
+// exportBundle writes the cached versions/editions in buildIDs, for the
+// platform specified by the --os and --arch flags, to the bundle file
+// specified by the --output flag.
+func exportBundle(cmd *cobra.Command, buildIDs []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	p, err := platformFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	tags, err := repository.CachedTags(app.CacheDirPath)
+	if err != nil {
+		return err
+	}
+
+	d, err := bundle.Export(output, app.CacheDirPath, p, buildIDs, tags)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Bundle with %d version(s) written to %s\n", len(d.Builds), output)
+
+	return nil
+}
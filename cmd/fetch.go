@@ -0,0 +1,93 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+)
+
+// fetchCmd represents the fetch command.
+var fetchCmd = &cobra.Command{
+	Use:   "fetch [version] | [flags]",
+	Short: "Download a version/edition to the cache without using it",
+	Long: `Displays a list of recent Hugo releases, prompting you to select a
+version/edition. It then downloads, verifies, extracts, and caches the release
+asset without changing the version/edition used by the current directory.
+
+Use the --os and --arch flags to fetch the release asset for another platform.
+Release assets for other platforms are cached separately, and are never run.
+
+  ` + app.Name + ` fetch v0.159.1/extended
+  ` + app.Name + ` fetch v0.159.1/extended --os windows --arch amd64
+  ` + app.Name + ` fetch latest --os darwin
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		version := ""
+		if len(args) > 0 {
+			version = args[0]
+		}
+		p, err := platformFromFlags(cmd)
+		cobra.CheckErr(err)
+		err = fetch(version, p)
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the fetch command with the root command.
+func init() {
+	rootCmd.AddCommand(fetchCmd)
+	addPlatformFlags(fetchCmd)
+}
+
+// fetch downloads and caches the release asset for version on platform p.
+func fetch(version string, p repository.Platform) error {
+	version, err := resolveAlias(version)
+	if err != nil {
+		return err
+	}
+
+	asset, err := resolveAsset(os.Stdout, version, p, "Select a version to fetch", "Select an edition")
+	if err != nil {
+		return err
+	}
+	if asset == nil {
+		return nil // user cancelled
+	}
+
+	exists, err := helpers.Exists(asset.DirPath(app.CacheDirPath))
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		fmt.Printf("%s/%s for %s is already cached.\n", asset.Tag, asset.Edition, p)
+	} else {
+		err := downloadAndCache(os.Stdout, asset)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Executable:", asset.ExecPath(app.CacheDirPath))
+
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+)
+
+// addFromFileFlags adds the --fromFile, --checksums, and --sha256 flags to cmd.
+func addFromFileFlags(cmd *cobra.Command) {
+	cmd.Flags().String("fromFile", "", "Path of a release archive to cache instead of downloading\none (e.g. hugo_extended_0.153.0_linux-amd64.tar.gz)")
+	cmd.Flags().String("checksums", "", "Path of the checksums file with which to verify the\narchive specified by --fromFile; the default is the\nchecksums file in the same directory as the archive")
+	cmd.Flags().String("sha256", "", "SHA-256 hex digest with which to verify the archive\nspecified by --fromFile")
+	cmd.MarkFlagsMutuallyExclusive("checksums", "sha256")
+}
+
+// assetFromFileFlags returns the asset for the release archive specified by
+// the --fromFile flag of cmd for platform p, or nil if the flag is not set.
+func assetFromFileFlags(cmd *cobra.Command, args []string, p repository.Platform) (*repository.Asset, error) {
+	file, err := cmd.Flags().GetString("fromFile")
+	if err != nil {
+		return nil, err
+	}
+	checksums, err := cmd.Flags().GetString("checksums")
+	if err != nil {
+		return nil, err
+	}
+	digest, err := cmd.Flags().GetString("sha256")
+	if err != nil {
+		return nil, err
+	}
+
+	if file == "" {
+		if checksums != "" || digest != "" {
+			return nil, errors.New("the --checksums and --sha256 flags require the --fromFile flag")
+		}
+		return nil, nil
+	}
+	if len(args) > 0 {
+		return nil, errors.New("the --fromFile flag cannot be used with a version argument")
+	}
+
+	return assetFromFile(file, checksums, digest, p)
+}
+
+// assetFromFile returns the asset for the release archive at file for
+// platform p, inferring its tag and edition from its file name. The archive is
+// verified against the SHA-256 hex digest digest if not empty, otherwise
+// against the checksums file at checksums, or, if checksums is empty, the
+// checksums file in the same directory as the archive.
+func assetFromFile(file, checksums, digest string, p repository.Platform) (*repository.Asset, error) {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(file); err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(file)
+	tag, edition, ok := repository.ParseAssetName(name, p)
+	if !ok {
+		for _, pp := range repository.Platforms {
+			if _, _, ok := repository.ParseAssetName(name, pp); ok {
+				return nil, fmt.Errorf("%s is a release asset for %s, not %s: use the --os and --arch flags to specify its platform", name, pp, p)
+			}
+		}
+		return nil, fmt.Errorf("%s is not the name of a release asset: the tag and edition are inferred from the file name, as published (e.g. %s)", name, exampleAssetName(p))
+	}
+
+	asset := newAsset(p)
+	asset.Tag = tag
+	asset.Edition = edition
+	err = asset.SetURLFromEditions(map[string]string{edition: helpers.PathToFileURL(file)})
+	if err != nil {
+		return nil, err
+	}
+
+	asset.Checksum, err = expectedDigest(asset, file, checksums, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, checkLibc(asset)
+}
+
+// expectedDigest returns the expected SHA-256 hex digest of the release
+// archive at file for asset a, as described by assetFromFile.
+func expectedDigest(a *repository.Asset, file, checksums, digest string) (string, error) {
+	name := filepath.Base(file)
+
+	if digest != "" {
+		digest = strings.ToLower(digest)
+		if b, err := hex.DecodeString(digest); err != nil || len(b) != 32 {
+			return "", fmt.Errorf("invalid SHA-256 digest %q: must be 64 hexadecimal digits", digest)
+		}
+		return digest, nil
+	}
+
+	if checksums == "" {
+		// Old releases published a checksums file for each edition.
+		version := strings.TrimPrefix(a.Tag, "v")
+		prefix, _, _ := strings.Cut(name, "_"+version+"_")
+		for _, n := range []string{prefix + "_" + version + "_checksums.txt", "hugo_" + version + "_checksums.txt"} {
+			path := filepath.Join(filepath.Dir(file), n)
+			if _, err := os.Stat(path); err == nil {
+				checksums = path
+				break
+			}
+		}
+		if checksums == "" {
+			return "", fmt.Errorf("unable to verify %s: no checksums file found in %s: specify a checksums file with --checksums, or a digest with --sha256", name, filepath.Dir(file))
+		}
+	}
+
+	f, err := os.Open(checksums)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	sums, err := repository.ParseChecksums(f)
+	if err != nil {
+		return "", err
+	}
+	expected, ok := sums[name]
+	if !ok {
+		return "", fmt.Errorf("unable to verify %s: %s does not list it", name, checksums)
+	}
+	return expected, nil
+}
+
+// exampleAssetName returns the name of the extended edition release asset of
+// a recent release for platform p, or of linux/amd64 if none is published for
+// p.
+func exampleAssetName(p repository.Platform) string {
+	if name, ok := repository.AssetName("v0.153.0", "extended", p); ok {
+		return name
+	}
+	name, _ := repository.AssetName("v0.153.0", "extended", repository.Platform{OS: "linux", Arch: "amd64"})
+	return name
+}
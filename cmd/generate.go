@@ -0,0 +1,115 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gh "github.com/jmooring/hvm/github"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+)
+
+// generateCmd represents the index generate command.
+var generateCmd = &cobra.Command{
+	Use:   "generate [flags]",
+	Short: "Generate a release index",
+	Long: `Generate a release index from the Hugo releases on GitHub, or from a directory
+of downloaded release archives.
+
+When generating from a directory, the asset URLs in the index are the archive
+file names, relative to the location of the index. Serve the index from the
+same directory as the archives, or use the --baseURL flag to make the URLs
+absolute.
+
+  ` + app.Name + ` index generate -o index.json
+  ` + app.Name + ` index generate --fromDir /srv/hugo -o /srv/hugo/index.json
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := generateIndex(cmd)
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the generate command with the index command.
+func init() {
+	indexCmd.AddCommand(generateCmd)
+	generateCmd.Flags().String("fromDir", "", "Generate the index from the release archives in this\ndirectory instead of from GitHub")
+	generateCmd.Flags().String("baseURL", "", "Base URL of the release archives when using --fromDir")
+	generateCmd.Flags().Int("limit", -1, "Maximum number of releases to include when generating\nfrom GitHub, newest first; -1 includes all releases")
+	generateCmd.Flags().StringP("output", "o", "", "Write the index to this file instead of stdout")
+}
+
+// generateIndex generates a release index and writes it to stdout or to the
+// file specified by the --output flag.
+func generateIndex(cmd *cobra.Command) (retErr error) {
+	fromDir, err := cmd.Flags().GetString("fromDir")
+	if err != nil {
+		return err
+	}
+	baseURL, err := cmd.Flags().GetString("baseURL")
+	if err != nil {
+		return err
+	}
+	limit, err := cmd.Flags().GetInt("limit")
+	if err != nil {
+		return err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+
+	var ix *repository.Index
+	if fromDir != "" {
+		ix, err = repository.GenerateIndexFromDir(fromDir, baseURL)
+	} else {
+		if baseURL != "" {
+			return fmt.Errorf("the --baseURL flag requires the --fromDir flag")
+		}
+		client := gh.NewClient(config.GitHubToken)
+		ix, err = repository.GenerateIndex(client, app.ManagedApp.RepositoryOwner, app.ManagedApp.RepositoryName, newHTTPClient(), limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		return ix.Write(os.Stdout)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+
+	err = ix.Write(f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Release index with %d release(s) written to %s\n", len(ix.Releases), output)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// historyCmd represents the history command.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Display the versions used for the current directory",
+	Long: `Display the versions/editions written to the ` + app.DotFileName + ` file of the current
+directory, most recent first, and when each was written. The history is stored
+in the configuration directory, not in the project.
+
+Switch back to the version/edition used before the current one:
+
+  ` + app.Name + ` use -
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := history()
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the history command with the root command.
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
+
+// history displays the versions/editions written to the dot file of the
+// current directory, most recent first.
+func history() error {
+	entries, err := newDotFileManager().History()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No versions have been used for the current directory: run \"%s use\" to select one.\n", app.Name)
+		return nil
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		fmt.Printf("%s  %s\n", entries[i].Time.Local().Format("2006-01-02 15:04:05"), entries[i].BuildID)
+	}
+	return nil
+}
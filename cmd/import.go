@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmooring/hvm/bundle"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the bundle import command.
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import cached versions from a bundle",
+	Long: `Import the versions/editions of the Hugo executable in a bundle file into the
+cache for the platform of the bundle, replacing cached versions/editions with
+the same name. Each file in the bundle is verified against its manifest before
+anything is imported. No network access is required.
+
+  ` + app.Name + ` bundle import hugo-bundle.tar.gz
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := importBundle(args[0])
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the import command with the bundle command.
+func init() {
+	bundleCmd.AddCommand(importCmd)
+}
+
+// importBundle imports the versions/editions in the named bundle file into
+// the cache and adds the bundle's release tags to the cached tag list.
+func importBundle(name string) error {
+	d, err := bundle.Import(name, app.CacheDirPath, app.StagingDirPath, extractLimits())
+	if err != nil {
+		return limitErrorWithHint(err)
+	}
+
+	tags := d.Tags
+	for _, id := range d.Builds {
+		tag, _, _ := strings.Cut(id, "/")
+		tags = append(tags, tag)
+	}
+	err = repository.MergeCachedTags(app.CacheDirPath, tags)
+	if err != nil {
+		return err
+	}
+
+	p := repository.Platform{OS: d.OS, Arch: d.Arch}
+	for _, id := range d.Builds {
+		if p != repository.HostPlatform() {
+			fmt.Printf("Imported %s for %s\n", id, p)
+			continue
+		}
+		fmt.Printf("Imported %s\n", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// indexCmd represents the index command.
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Manage release indexes",
+	Long: `Manage release indexes.
+
+A release index is a JSON document that lists the tags, editions, per-platform
+archive URLs, and checksums of Hugo releases. Serve a release index from a
+static file server and set the "releaseSource" configuration value to its URL
+to use it instead of the GitHub API.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// init registers the index command with the root command.
+func init() {
+	rootCmd.AddCommand(indexCmd)
+}
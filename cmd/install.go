@@ -22,7 +22,9 @@ import (
 	"path/filepath"
 	"slices"
 
+	"github.com/jmooring/hvm/cache"
 	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/repository"
 	"github.com/spf13/cobra"
 )
 
@@ -51,13 +53,36 @@ Bypass the selection menu by specifying a version or version/edition:
 
   ` + app.Name + ` install latest
   ` + app.Name + ` install latest/standard
+
+  ` + app.Name + ` install @production
+
+Use the --os and --arch flags to install the release asset for another
+platform instead. It is placed in the "default" directory within the
+platform's cache directory, and is not added to your PATH.
+
+Use the --fromFile flag to install a release archive that you have already
+downloaded, instead of downloading it. The version/edition is inferred from the
+file name, and the archive is verified against the checksums file in the same
+directory, the checksums file specified by the --checksums flag, or the digest
+specified by the --sha256 flag:
+
+  ` + app.Name + ` install --fromFile hugo_extended_0.153.0_linux-amd64.tar.gz
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		version := ""
 		if len(args) > 0 {
 			version = args[0]
 		}
-		err := install(version)
+		p, err := platformFromFlags(cmd)
+		cobra.CheckErr(err)
+		asset, err := assetFromFileFlags(cmd, args, p)
+		cobra.CheckErr(err)
+		if asset != nil {
+			err = installAsset(asset, p)
+			cobra.CheckErr(err)
+			return
+		}
+		err = install(version, p)
 		cobra.CheckErr(err)
 	},
 }
@@ -65,12 +90,20 @@ Bypass the selection menu by specifying a version or version/edition:
 // init registers the install command with the root command.
 func init() {
 	rootCmd.AddCommand(installCmd)
+	addPlatformFlags(installCmd)
+	addFromFileFlags(installCmd)
 }
 
 // install sets the version/edition to use when version management is disabled
-// for the current directory.
-func install(version string) error {
-	asset, err := resolveAsset(version, "Select a version to install", "Select an edition")
+// for the current directory. If p is not the host platform, the executable is
+// placed in the "default" directory within the platform's cache directory.
+func install(version string, p repository.Platform) error {
+	version, err := resolveAlias(version)
+	if err != nil {
+		return err
+	}
+
+	asset, err := resolveAsset(os.Stdout, version, p, "Select a version to install", "Select an edition")
 	if err != nil {
 		return err
 	}
@@ -78,19 +111,43 @@ func install(version string) error {
 		return nil // user cancelled
 	}
 
-	exists, err := helpers.Exists(filepath.Join(app.CacheDirPath, asset.Tag, asset.Edition))
+	return installAsset(asset, p)
+}
+
+// installAsset installs the release asset for platform p, caching it if it is
+// not already cached.
+func installAsset(asset *repository.Asset, p repository.Platform) error {
+	exists, err := helpers.Exists(asset.DirPath(app.CacheDirPath))
 	if err != nil {
 		return err
 	}
 
 	if !exists {
-		err := downloadAndCache(asset)
+		err := downloadAndCache(os.Stdout, asset)
+		if err != nil {
+			return err
+		}
+	}
+
+	if p != repository.HostPlatform() {
+		defaultDirPath := filepath.Join(app.CacheDirPath, cache.PlatformsDirName, p.OS+"-"+p.Arch, app.DefaultDirName)
+		err = helpers.CopyFile(asset.ExecPath(app.CacheDirPath), filepath.Join(defaultDirPath, asset.ExecName))
 		if err != nil {
 			return err
 		}
+		fmt.Printf("Installation of %s/%s for %s complete.\n", asset.Tag, asset.Edition, p)
+		fmt.Printf("The executable is in %s.\n", defaultDirPath)
+		return nil
 	}
 
-	err = helpers.CopyFile(asset.ExecPath(app.CacheDirPath), filepath.Join(app.CacheDirPath, app.DefaultDirName, asset.ExecName))
+	return installDefault(asset)
+}
+
+// installDefault copies the cached executable of asset, for the host
+// platform, to the "default" cache directory, and prompts the user to add the
+// directory to the PATH environment variable if it is not there.
+func installDefault(asset *repository.Asset) error {
+	err := helpers.CopyFile(asset.ExecPath(app.CacheDirPath), filepath.Join(app.CacheDirPath, app.DefaultDirName, asset.ExecName))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jmooring/hvm/cache"
+	"github.com/jmooring/hvm/links"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+)
+
+// linkCmd represents the link command.
+var linkCmd = &cobra.Command{
+	Use:   "link <name> <path>",
+	Short: "Register a locally built Hugo executable as local/<name>",
+	Long: `Register a locally built Hugo executable, such as a build from a clone of the
+Hugo repository, as local/<name>. The path may be the executable or the
+directory containing it.
+
+The executable is not copied to the cache, so rebuilding it updates the linked
+build. Use local/<name> wherever you would use a version/edition:
+
+  ` + app.Name + ` link patched ~/src/hugo/hugo
+  ` + app.Name + ` use local/patched
+
+Linked builds are stored in the configuration directory, separately from the
+cache, and are not removed by the clean command. Run "` + app.Name + ` unlink <name>" to
+remove one.
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := link(args[0], args[1])
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the link command with the root command.
+func init() {
+	rootCmd.AddCommand(linkCmd)
+}
+
+// link registers the Hugo executable at path as the linked build name.
+func link(name, path string) error {
+	err := links.ValidateName(name)
+	if err != nil {
+		return err
+	}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		path = filepath.Join(path, cache.ExecName())
+	}
+
+	bi, line, err := runVersion(path)
+	if err != nil {
+		return fmt.Errorf("unable to link %s: %w", path, err)
+	}
+	if bi.Platform != repository.HostPlatform() {
+		return fmt.Errorf("unable to link %s: it was built for %s, not %s", path, bi.Platform, repository.HostPlatform())
+	}
+
+	r, err := links.Read(app.LinksFilePath)
+	if err != nil {
+		return err
+	}
+	r[name] = path
+	err = r.Write(app.LinksFilePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Linked %s to %s (%s).\n", links.BuildID(name), path, line)
+
+	return nil
+}
+
+// linkedExecPath returns the path of the executable of the linked build name.
+func linkedExecPath(name string) (string, error) {
+	r, err := links.Read(app.LinksFilePath)
+	if err != nil {
+		return "", err
+	}
+	path, ok := r[name]
+	if !ok {
+		return "", fmt.Errorf("%s is not linked: run \"%s link %s <path>\" to link it", links.BuildID(name), app.Name, name)
+	}
+	return path, nil
+}
+
+// useLinked sets the linked build name as the build to use for the current
+// directory.
+func useLinked(name string) error {
+	path, err := linkedExecPath(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("unable to use %s: %w", links.BuildID(name), err)
+	}
+
+	err = newDotFileManager().Write(links.BuildID(name))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Using %s (%s).\n", links.BuildID(name), path)
+
+	return nil
+}
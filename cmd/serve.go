@@ -0,0 +1,206 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+)
+
+// indexFileName is the name under which the serve command publishes its
+// release index.
+const indexFileName = "index.json"
+
+// errNoArchives is returned by archiveServer.releaseIndex if the directory
+// contains no release archives to describe.
+var errNoArchives = errors.New("no release archives")
+
+// serveCmd represents the serve command.
+var serveCmd = &cobra.Command{
+	Use:   "serve [flags]",
+	Short: "Serve cached release archives to other machines",
+	Long: `Serve the release archives retained in the cache, and a release index
+describing them, over HTTP. Set the "keepArchives" configuration value to true
+to retain release archives when downloading; otherwise, the command fails if
+the cache has no release archives to serve.
+
+Other machines can download release archives from this server by adding its
+URL to the "peers" configuration value. Downloads from a peer are verified
+against the checksums published by the release source, and are not used if no
+checksums are available.
+
+  ` + app.Name + ` serve --addr :8080
+
+The release index is available at /` + indexFileName + `, and may be used as the
+"releaseSource" configuration value on machines that trust this server.
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := serve(cmd)
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the serve command with the root command.
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().String("addr", ":8080", "TCP address to listen on")
+	serveCmd.Flags().String("dir", "", "Serve the release archives in this directory instead\nof those retained in the cache")
+}
+
+// serve serves the release archives in the archives directory, or in the
+// directory specified by the --dir flag, until interrupted. It returns an
+// error if the archives directory has no release archives and the
+// keepArchives configuration value is false.
+func serve(cmd *cobra.Command) error {
+	addr, err := cmd.Flags().GetString("addr")
+	if err != nil {
+		return err
+	}
+	dir, err := cmd.Flags().GetString("dir")
+	if err != nil {
+		return err
+	}
+	cached := dir == ""
+	if cached {
+		dir = app.ArchivesDirPath
+		err = os.MkdirAll(dir, 0o755)
+		if err != nil {
+			return err
+		}
+	}
+
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	// Release archives are retained in the cache only if keepArchives is
+	// true, so an empty archives directory stays empty otherwise.
+	ok, err := repository.HasArchives(dir)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !ok && cached && !config.KeepArchives:
+		return fmt.Errorf("there are no release archives to serve in %s: set the keepArchives configuration value to true to retain release archives when downloading", dir)
+	case !ok:
+		fmt.Fprintf(os.Stderr, "Warning: there are no release archives in %s\n", dir)
+	case cached && !config.KeepArchives:
+		fmt.Fprintf(os.Stderr, "Warning: the keepArchives configuration value is false: release archives downloaded from now on will not be retained or served\n")
+	}
+
+	fmt.Printf("Serving release archives in %s on %s\n", dir, addr)
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           newArchiveServer(dir),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
+
+// An archiveServer serves the release archives in a directory and a release
+// index describing them. The index is regenerated when the directory changes.
+type archiveServer struct {
+	dir   string
+	files http.Handler
+
+	mu      sync.Mutex
+	modTime time.Time // modification time of dir when index was generated
+	index   []byte
+}
+
+// newArchiveServer returns an archiveServer for the release archives in dir.
+func newArchiveServer(dir string) *archiveServer {
+	return &archiveServer{
+		dir:   dir,
+		files: http.FileServer(http.Dir(dir)),
+	}
+}
+
+// ServeHTTP serves the release index or a release archive. A request for the
+// index fails with status 404 if there are no release archives, and with
+// status 500 if the index cannot be generated.
+func (s *archiveServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/"+indexFileName {
+		s.files.ServeHTTP(w, r)
+		return
+	}
+
+	index, modTime, err := s.releaseIndex()
+	if errors.Is(err, errNoArchives) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	http.ServeContent(w, r, indexFileName, modTime, bytes.NewReader(index))
+}
+
+// releaseIndex returns the release index for the archives in s.dir and the
+// time it was last modified, regenerating the index if s.dir has changed. It
+// returns errNoArchives if s.dir contains no release archives.
+func (s *archiveServer) releaseIndex() ([]byte, time.Time, error) {
+	fi, err := os.Stat(s.dir)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	ok, err := repository.HasArchives(s.dir)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if !ok {
+		return nil, time.Time{}, fmt.Errorf("%w in %s", errNoArchives, s.dir)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index != nil && fi.ModTime().Equal(s.modTime) {
+		return s.index, s.modTime, nil
+	}
+
+	// Asset URLs are relative to the index.
+	ix, err := repository.GenerateIndexFromDir(s.dir, "")
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var buf bytes.Buffer
+	err = ix.Write(&buf)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	s.index = buf.Bytes()
+	s.modTime = fi.ModTime()
+	return s.index, s.modTime, nil
+}
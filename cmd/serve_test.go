@@ -0,0 +1,104 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmooring/hvm/repository"
+)
+
+// TestArchiveServer verifies that the archive server publishes a release
+// index with relative asset URLs and serves the archives it describes.
+func TestArchiveServer(t *testing.T) {
+	const archiveFile = "hugo_extended_0.153.0_linux-amd64.tar.gz"
+	const content = "fake archive content"
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, archiveFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(newArchiveServer(dir))
+	defer ts.Close()
+
+	ix, err := repository.LoadIndex(ts.URL+"/"+indexFileName, &http.Client{})
+	if err != nil {
+		t.Fatalf("LoadIndex error: %v", err)
+	}
+	if len(ix.Releases) != 1 || ix.Releases[0].Tag != "v0.153.0" {
+		t.Fatalf("LoadIndex: unexpected releases %+v", ix.Releases)
+	}
+	a := ix.Releases[0].Assets[0]
+	if want := ts.URL + "/" + archiveFile; a.URL != want {
+		t.Fatalf("asset URL: want %s got %s", want, a.URL)
+	}
+	if a.SHA256 != digest(content) {
+		t.Fatalf("asset digest: want %s got %s", digest(content), a.SHA256)
+	}
+
+	resp, err := http.Get(a.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || string(got) != content {
+		t.Fatalf("archive: want 200 %q got %d %q", content, resp.StatusCode, got)
+	}
+}
+
+// TestArchiveServer_Empty verifies that the archive server responds with an
+// error when the directory contains no release archives.
+func TestArchiveServer_Empty(t *testing.T) {
+	ts := httptest.NewServer(newArchiveServer(t.TempDir()))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/" + indexFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status: want %d got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+// TestArchiveServer_Error verifies that the archive server responds with a
+// server error when the release index cannot be generated.
+func TestArchiveServer_Error(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archives")
+	ts := httptest.NewServer(newArchiveServer(dir))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/" + indexFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("status: want %d got %d", http.StatusInternalServerError, resp.StatusCode)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/jmooring/hvm/aliases"
+	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/links"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+)
+
+// sessionVersionEnvVar is the name of the environment variable that overrides
+// the dot file for the current terminal session.
+const sessionVersionEnvVar = "HVM_HUGO_VERSION"
+
+// shellExports maps each supported shell to the format of a statement that
+// sets an environment variable, given its name and value.
+var shellExports = map[string]string{
+	"bash":       "export %s='%s'\n",
+	"fish":       "set -gx %s '%s'\n",
+	"powershell": "$env:%s = '%s'\n",
+	"zsh":        "export %s='%s'\n",
+}
+
+// shellCmd represents the shell command.
+var shellCmd = &cobra.Command{
+	Use:   "shell [version] | [flags]",
+	Short: "Print a statement that selects a version/edition for the terminal session",
+	Long: `Print a statement that sets the ` + sessionVersionEnvVar + ` environment variable, which
+overrides the ` + app.DotFileName + ` file for the current terminal session, and therefore for the
+alias functions generated by the "gen alias" command. The ` + app.DotFileName + ` file is not
+changed. The release asset is downloaded and cached if necessary.
+
+Specify the version/edition as you would with the "use" command, or omit it to
+select one. Evaluate the output with your shell:
+
+  eval "$(` + app.Name + ` shell v0.153.0/extended)"                    # bash or zsh
+  ` + app.Name + ` shell v0.153.0/extended | source                     # fish
+  ` + app.Name + ` shell v0.153.0/extended | Invoke-Expression          # PowerShell
+
+The shell is determined from the SHELL environment variable, or is PowerShell
+on Windows. Use the --shell flag to specify another. To stop overriding the
+` + app.DotFileName + ` file, unset the ` + sessionVersionEnvVar + ` environment variable.
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version := ""
+		if len(args) > 0 {
+			version = args[0]
+		}
+		sh, err := cmd.Flags().GetString("shell")
+		cobra.CheckErr(err)
+		err = shell(os.Stdout, version, sh)
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the shell command with the root command.
+func init() {
+	rootCmd.AddCommand(shellCmd)
+	shellCmd.Flags().String("shell", "", "Shell for which to print the statement: "+strings.Join(shellNames(), ", ")+";\nthe default is determined from the SHELL environment variable")
+}
+
+// shell writes to w a statement for the shell sh, or the detected shell if sh
+// is empty, that sets the session version environment variable to version,
+// caching the release asset for the host platform if necessary.
+func shell(w io.Writer, version, sh string) error {
+	if sh == "" {
+		var err error
+		sh, err = detectShell()
+		if err != nil {
+			return err
+		}
+	}
+	format, ok := shellExports[sh]
+	if !ok {
+		return fmt.Errorf("unsupported shell %q: must be one of %s", sh, shellList())
+	}
+
+	// Write prompts and progress to stderr, so that w contains only the
+	// statement to evaluate.
+	buildID, err := sessionVersion(os.Stderr, version)
+	if err != nil {
+		return err
+	}
+	if buildID == "" {
+		return nil // user cancelled
+	}
+
+	_, err = fmt.Fprintf(w, format, sessionVersionEnvVar, buildID)
+	return err
+}
+
+// sessionVersion returns the build identifier to which to set the session
+// version environment variable for version, caching the release asset for
+// the host platform if necessary, and writing prompts and progress to w. It
+// returns an empty string if the user cancelled an interactive prompt.
+func sessionVersion(w io.Writer, version string) (string, error) {
+	if name, ok := links.ParseBuildID(version); ok {
+		path, err := linkedExecPath(name)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("unable to use %s: %w", version, err)
+		}
+		return version, nil
+	}
+
+	// Refer to an alias, rather than the version/edition to which it refers,
+	// as the dot file does.
+	var ref string
+	if _, ok := aliases.ParseRef(version); ok {
+		ref = version
+	}
+	version, err := resolveAlias(version)
+	if err != nil {
+		return "", err
+	}
+
+	asset, err := resolveAsset(w, version, repository.HostPlatform(), "Select a version to use for this terminal session", "Select an edition")
+	if err != nil {
+		return "", err
+	}
+	if asset == nil {
+		return "", nil // user cancelled
+	}
+
+	exists, err := helpers.Exists(asset.DirPath(app.CacheDirPath))
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		err := downloadAndCache(w, asset)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if ref != "" {
+		return ref, nil
+	}
+	return asset.Tag + "/" + asset.Edition, nil
+}
+
+// sessionBuildID returns the build identifier specified by the session
+// version environment variable, or an empty string if it is not set.
+func sessionBuildID() (string, error) {
+	v := strings.TrimSpace(os.Getenv(sessionVersionEnvVar))
+	if v == "" {
+		return "", nil
+	}
+	if _, ok := links.ParseBuildID(v); ok {
+		return v, nil
+	}
+	if _, ok := aliases.ParseRef(v); ok {
+		return v, nil
+	}
+
+	tag, edition, _ := strings.Cut(v, "/")
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	if !semver.IsValid(tag) || !slices.Contains(repository.ValidEditions, edition) {
+		return "", fmt.Errorf("the %s environment variable is invalid: %q must be a version/edition (e.g. v0.153.0/extended), @<alias>, or local/<name>: run \"%s shell\" to select a version, or unset the variable", sessionVersionEnvVar, v, app.Name)
+	}
+	return tag + "/" + edition, nil
+}
+
+// detectShell returns the shell of the current terminal session.
+func detectShell() (string, error) {
+	if runtime.GOOS == "windows" {
+		return "powershell", nil
+	}
+	name := filepath.Base(os.Getenv("SHELL"))
+	if name == "pwsh" {
+		return "powershell", nil
+	}
+	if _, ok := shellExports[name]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("unable to determine the shell from the SHELL environment variable: specify %s with the --shell flag", shellList())
+}
+
+// shellNames returns the sorted names of the supported shells.
+func shellNames() []string {
+	return slices.Sorted(maps.Keys(shellExports))
+}
+
+// shellList returns the names of the supported shells as a human-readable
+// list.
+func shellList() string {
+	list, _ := helpers.JoinWithConjunction(shellNames(), "or")
+	return list
+}
@@ -17,15 +17,20 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"strings"
 
 	"github.com/jmooring/hvm/cache"
-	"github.com/jmooring/hvm/dotfile"
 	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/links"
+	"github.com/jmooring/hvm/repository"
 	"github.com/spf13/cobra"
 	"golang.org/x/mod/semver"
 )
@@ -35,7 +40,10 @@ var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Display the status",
 	Long: `Display a list of cached assets, the size of the cache, and the cache
-location. The "default" directory created by the "install" command is excluded.`,
+location. The "default" directory created by the "install" command is excluded.
+Also display the locally built executables registered with the "link" command.
+On Linux, also display the C standard library of this machine, which determines
+the editions that can run on it.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := status(cmd)
 		cobra.CheckErr(err)
@@ -46,10 +54,12 @@ location. The "default" directory created by the "install" command is excluded.`
 func init() {
 	rootCmd.AddCommand(statusCmd)
 	statusCmd.Flags().Bool("printExecPath", false, `Based on the version specified in the `+app.DotFileName+` file,
+or in the `+sessionVersionEnvVar+` environment variable,
 print the path to Hugo executable, otherwise
 return exit code 1. This path is not verified, and the
 executable may not exist.`)
 	statusCmd.Flags().Bool("printExecPathCached", false, `Based on the version specified in the `+app.DotFileName+` file,
+or in the `+sessionVersionEnvVar+` environment variable,
 print the path to Hugo executable if cached, otherwise
 return exit code 1.`)
 	statusCmd.MarkFlagsMutuallyExclusive("printExecPath", "printExecPathCached")
@@ -71,11 +81,30 @@ func status(cmd *cobra.Command) error {
 		return err
 	}
 
-	dm := dotfile.NewManager(app.DotFilePath, app.DotFileName, app.Name, config.DefaultEdition)
-	buildID, err := dm.Read()
+	// The session version environment variable overrides the dot file.
+	buildID, err := sessionBuildID()
 	if err != nil {
 		return err
 	}
+	source := sessionVersionEnvVar
+	if buildID == "" {
+		buildID, source, err = newDotFileManager().ReadWithSource()
+		if err != nil {
+			return err
+		}
+	}
+
+	// The dot file may refer to an alias (e.g. "@production"), which is
+	// described along with the version/edition to which it refers.
+	dotFileBuildID := buildID
+	buildID, err = resolveAlias(buildID)
+	if err != nil {
+		return err
+	}
+	described := buildID
+	if dotFileBuildID != buildID {
+		described = dotFileBuildID + " (" + buildID + ")"
+	}
 
 	// buildID is "version/edition" (e.g. "v0.160.0/extended") or empty.
 	var version, edition string
@@ -88,6 +117,13 @@ func status(cmd *cobra.Command) error {
 	}
 
 	execPath := filepath.Join(app.CacheDirPath, version, edition, cache.ExecName())
+	linkName, linked := links.ParseBuildID(buildID)
+	if linked {
+		execPath, err = linkedExecPath(linkName)
+		if err != nil {
+			return err
+		}
+	}
 	execPathExists, err := helpers.Exists(execPath)
 	if err != nil {
 		return err
@@ -121,16 +157,28 @@ func status(cmd *cobra.Command) error {
 		fmt.Println("Version management is disabled for the current directory.")
 	} else {
 		if execPathExists {
-			fmt.Printf("The current directory is configured to use Hugo %s.\n", buildID)
+			if source == sessionVersionEnvVar {
+				fmt.Printf("The current terminal session is configured to use Hugo %s.\n", described)
+				fmt.Printf("The %s environment variable overrides the %s file.\n", sessionVersionEnvVar, app.DotFileName)
+			} else {
+				fmt.Printf("The current directory is configured to use Hugo %s.\n", described)
+				if source != app.DotFileName {
+					fmt.Printf("The version was read from the %s file.\n", source)
+				}
+			}
+		} else if linked {
+			return fmt.Errorf("the executable linked as %s does not exist: %s: rebuild it, or run \"%s link %s <path>\" to link another", buildID, execPath, app.Name, linkName)
+		} else if source == sessionVersionEnvVar {
+			return fmt.Errorf("the %s environment variable refers to a Hugo version (%s) that is not cached: run \"%s fetch %s\" to get it", sessionVersionEnvVar, described, app.Name, dotFileBuildID)
 		} else {
-			fmt.Printf("The %s file in the current directory refers to a Hugo\n", app.DotFileName)
-			fmt.Printf("version (%s) that is not cached.\n", buildID)
+			fmt.Printf("The %s file in the current directory refers to a Hugo\n", source)
+			fmt.Printf("version (%s) that is not cached.\n", described)
 			fmt.Println()
 			if promptYesNo("Would you like to get it now?", true) {
-				err = use(buildID)
+				err = useDotFileVersion(dotFileBuildID, source, repository.HostPlatform())
 				if err != nil {
 					theFix := fmt.Sprintf("run \"%[1]s use\" to select a version, or \"%[1]s disable\" to remove the file", app.Name)
-					return fmt.Errorf("unable to get %s (%s): %w: %s", app.DotFileName, buildID, err, theFix)
+					return fmt.Errorf("unable to get %s (%s): %w: %s", source, described, err, theFix)
 				}
 			} else {
 				err = disable()
@@ -142,29 +190,60 @@ func status(cmd *cobra.Command) error {
 		}
 	}
 
-	// Get tag/edition entries; ignore app.DefaultDirName.
-	// Each entry is a tag directory whose subdirectories are editions.
-	sd, err := os.ReadDir(app.CacheDirPath)
+	// On Linux, report the C standard library, which determines the editions
+	// that can run on this machine.
+	if runtime.GOOS == "linux" {
+		info := detectLibc()
+		fmt.Println("C library:", info)
+		if !info.RunsGlibc() {
+			fmt.Println("Only the standard edition of Hugo can run on this machine.")
+		}
+		fmt.Println()
+	}
+
+	// List linked builds, which are not in the cache.
+	r, err := links.Read(app.LinksFilePath)
 	if err != nil {
 		return err
 	}
+	if len(r) > 0 {
+		fmt.Println("Linked builds:")
+		fmt.Println()
+		for _, name := range slices.Sorted(maps.Keys(r)) {
+			exists, err := helpers.Exists(r[name])
+			if err != nil {
+				return err
+			}
+			if exists {
+				fmt.Printf("%s: %s\n", links.BuildID(name), r[name])
+			} else {
+				fmt.Printf("%s: %s (missing)\n", links.BuildID(name), r[name])
+			}
+		}
+		fmt.Println()
+	}
 
-	var buildIDs []string
-	for _, d := range sd {
-		if !d.IsDir() || d.Name() == app.DefaultDirName {
+	// Get tag/edition entries for the host platform, followed by those for
+	// other platforms.
+	buildIDs, err := cachedBuildIDs(app.CacheDirPath)
+	if err != nil {
+		return err
+	}
+	platformDirs, err := os.ReadDir(filepath.Join(app.CacheDirPath, cache.PlatformsDirName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for _, pd := range platformDirs {
+		if !pd.IsDir() {
 			continue
 		}
-		tag := d.Name()
-		// List edition subdirectories within this tag directory.
-		editionDirs, err := os.ReadDir(filepath.Join(app.CacheDirPath, tag))
+		ids, err := cachedBuildIDs(filepath.Join(app.CacheDirPath, cache.PlatformsDirName, pd.Name()))
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: could not read cache directory %s: %s\n", tag, err)
-			continue
+			return err
 		}
-		for _, ed := range editionDirs {
-			if ed.IsDir() {
-				buildIDs = append(buildIDs, tag+"/"+ed.Name())
-			}
+		platform := strings.Replace(pd.Name(), "-", "/", 1)
+		for _, id := range ids {
+			buildIDs = append(buildIDs, id+" ("+platform+")")
 		}
 	}
 
@@ -200,3 +279,33 @@ func status(cmd *cobra.Command) error {
 
 	return nil
 }
+
+// cachedBuildIDs returns the tag/edition pairs cached in dir. Each entry of dir
+// is a tag directory whose subdirectories are editions; the "default",
+// archives, platforms, and staging directories are ignored.
+func cachedBuildIDs(dir string) ([]string, error) {
+	sd, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var buildIDs []string
+	for _, d := range sd {
+		if !d.IsDir() || d.Name() == app.DefaultDirName || d.Name() == app.ArchivesDirName || d.Name() == cache.PlatformsDirName || d.Name() == app.StagingDirName {
+			continue
+		}
+		tag := d.Name()
+		// List edition subdirectories within this tag directory.
+		editionDirs, err := os.ReadDir(filepath.Join(dir, tag))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read cache directory %s: %s\n", tag, err)
+			continue
+		}
+		for _, ed := range editionDirs {
+			if ed.IsDir() {
+				buildIDs = append(buildIDs, tag+"/"+ed.Name())
+			}
+		}
+	}
+	return buildIDs, nil
+}
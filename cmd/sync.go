@@ -0,0 +1,110 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jmooring/hvm/dotfile"
+	"github.com/jmooring/hvm/pins"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command.
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Update version pins in other files to match the " + app.DotFileName + " file",
+	Long: `Update the Hugo version and edition pinned by other configuration files in the
+current directory to match the ` + app.DotFileName + ` file, displaying the changes as a
+diff. The following pins are updated:
+
+  - HUGO_VERSION environment variables in netlify.toml
+  - hugo-version, extended, and withdeploy inputs of peaceiris/actions-hugo
+    steps in .github/workflows/*.yml and .github/workflows/*.yaml
+  - ARG HUGO_VERSION instructions in Dockerfile, Dockerfile.*, and *.Dockerfile
+  - the hugo-extended dependency in package.json
+
+Files that pin only a version are compared only by version.
+
+Use the --check flag to display the changes without writing them, exiting with
+a non-zero status if any pin differs, for use in pre-commit hooks and CI.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		check, err := cmd.Flags().GetBool("check")
+		cobra.CheckErr(err)
+		err = syncPins(check)
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the sync command with the root command.
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().Bool("check", false, "Display the changes without writing them, and exit with\na non-zero status if any pin differs")
+}
+
+// syncPins updates the version pins in the current directory to match the dot
+// file, or, if check is true, returns an error if any pin differs.
+func syncPins(check bool) error {
+	// Sync to the dot file only, never to a version file written by another
+	// tool, which is itself a pin.
+	dm := dotfile.NewManager(app.DotFilePath, app.DotFileName, app.Name, config.DefaultEdition, nil, "")
+	tag, edition, err := readDotFile(dm)
+	if err != nil {
+		return err
+	}
+
+	root := filepath.Dir(app.DotFilePath)
+	files, err := pins.Check(root, tag, edition)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		fmt.Printf("All version pins match %s/%s.\n", tag, edition)
+		return nil
+	}
+
+	for _, f := range files {
+		fmt.Print(f.Diff())
+	}
+
+	if check {
+		return fmt.Errorf("%d %s out of sync with the %s file: run \"%s sync\" to update", len(files), plural(len(files), "file is", "files are"), app.DotFileName, app.Name)
+	}
+
+	for _, f := range files {
+		err := os.WriteFile(filepath.Join(root, filepath.FromSlash(f.Path)), f.Content, f.Mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Updated %d %s to match %s/%s.\n", len(files), plural(len(files), "file", "files"), tag, edition)
+
+	return nil
+}
+
+// plural returns one if n is 1, otherwise many.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
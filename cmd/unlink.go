@@ -0,0 +1,62 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmooring/hvm/links"
+	"github.com/spf13/cobra"
+)
+
+// unlinkCmd represents the unlink command.
+var unlinkCmd = &cobra.Command{
+	Use:   "unlink <name>",
+	Short: "Remove a locally built Hugo executable registered with link",
+	Long: `Remove local/<name>, registered with the link command. The executable
+itself is not removed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := unlink(args[0])
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the unlink command with the root command.
+func init() {
+	rootCmd.AddCommand(unlinkCmd)
+}
+
+// unlink removes the linked build name from the registry.
+func unlink(name string) error {
+	r, err := links.Read(app.LinksFilePath)
+	if err != nil {
+		return err
+	}
+	if _, ok := r[name]; !ok {
+		return fmt.Errorf("%s is not linked", links.BuildID(name))
+	}
+	delete(r, name)
+	err = r.Write(app.LinksFilePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Unlinked %s.\n", links.BuildID(name))
+
+	return nil
+}
@@ -17,23 +17,32 @@ limitations under the License.
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/jmooring/hvm/aliases"
 	"github.com/jmooring/hvm/archive"
-	"github.com/jmooring/hvm/dotfile"
+	"github.com/jmooring/hvm/cache"
 	"github.com/jmooring/hvm/helpers"
+	"github.com/jmooring/hvm/links"
+	"github.com/jmooring/hvm/mirror"
 	"github.com/jmooring/hvm/repository"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // useCmd represents the use command.
@@ -56,25 +65,65 @@ Bypass the selection menu by specifying a version or version/edition:
 
   hvm use latest
   hvm use latest/standard
+
+  hvm use @production
+
+Switch back to the version/edition used before the current one:
+
+  hvm use -
+
+Use the --os and --arch flags to cache the release asset for another platform
+instead. The version/edition written to the ` + app.DotFileName + ` file is the same on every
+platform.
+
+Use the --fromFile flag to cache a release archive that you have already
+downloaded, instead of downloading it. The version/edition is inferred from the
+file name, and the archive is verified against the checksums file in the same
+directory, the checksums file specified by the --checksums flag, or the digest
+specified by the --sha256 flag:
+
+  hvm use --fromFile hugo_extended_0.153.0_linux-amd64.tar.gz
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		version := ""
 
+		p, err := platformFromFlags(cmd)
+		cobra.CheckErr(err)
+
 		useVersionInDotFile, err := cmd.Flags().GetBool("useVersionInDotFile")
 		cobra.CheckErr(err)
 
 		if useVersionInDotFile {
-			dm := dotfile.NewManager(app.DotFilePath, app.DotFileName, app.Name, config.DefaultEdition)
-			version, err = dm.Read()
+			// The session version environment variable overrides the dot
+			// file, as it does for the status command.
+			buildID, err := sessionBuildID()
 			cobra.CheckErr(err)
-			if version == "" {
+			source := sessionVersionEnvVar
+			if buildID == "" {
+				buildID, source, err = newDotFileManager().ReadWithSource()
+				cobra.CheckErr(err)
+			}
+			if buildID == "" {
 				cobra.CheckErr(fmt.Errorf("the current directory does not contain an %s file", app.DotFileName))
 			}
-		} else if len(args) > 0 {
+			err = useDotFileVersion(buildID, source, p)
+			cobra.CheckErr(err)
+			return
+		}
+
+		asset, err := assetFromFileFlags(cmd, args, p)
+		cobra.CheckErr(err)
+		if asset != nil {
+			err = useAsset(asset, "")
+			cobra.CheckErr(err)
+			return
+		}
+
+		if len(args) > 0 {
 			version = args[0]
 		}
 
-		err = use(version)
+		err = use(version, p)
 		cobra.CheckErr(err)
 	},
 }
@@ -82,12 +131,43 @@ Bypass the selection menu by specifying a version or version/edition:
 // init registers the use command with the root command.
 func init() {
 	rootCmd.AddCommand(useCmd)
-	useCmd.Flags().Bool("useVersionInDotFile", false, "Use the version specified by the "+app.DotFileName+" file\nfor the current directory")
+	useCmd.Flags().Bool("useVersionInDotFile", false, "Use the version specified by the "+sessionVersionEnvVar+"\nenvironment variable, the "+app.DotFileName+" file, or one of the\nversionFiles, for the current directory")
+	addPlatformFlags(useCmd)
+	addFromFileFlags(useCmd)
+	useCmd.MarkFlagsMutuallyExclusive("useVersionInDotFile", "fromFile")
 }
 
-// use sets the version/edition to use for the current directory.
-func use(version string) error {
-	asset, err := resolveAsset(version, "Select a version to use for the current directory", "Select an edition")
+// use sets the version/edition to use for the current directory, caching the
+// release asset for platform p.
+func use(version string, p repository.Platform) error {
+	// "-" refers to the version/edition used before the current one.
+	if version == "-" {
+		previous, err := newDotFileManager().Previous()
+		if err != nil {
+			return err
+		}
+		if previous == "" {
+			return fmt.Errorf("the current directory has no previous version: run \"%s history\" to display its history", app.Name)
+		}
+		version = previous
+	}
+
+	if name, ok := links.ParseBuildID(version); ok {
+		return useLinked(name)
+	}
+
+	// Write a reference to an alias, rather than the version/edition to
+	// which it refers, so that changing the alias changes the version.
+	var ref string
+	if _, ok := aliases.ParseRef(version); ok {
+		ref = version
+	}
+	version, err := resolveAlias(version)
+	if err != nil {
+		return err
+	}
+
+	asset, err := resolveAsset(os.Stdout, version, p, "Select a version to use for the current directory", "Select an edition")
 	if err != nil {
 		return err
 	}
@@ -95,7 +175,15 @@ func use(version string) error {
 		return nil // user cancelled
 	}
 
-	exists, err := helpers.Exists(filepath.Join(app.CacheDirPath, asset.Tag, asset.Edition))
+	return useAsset(asset, ref)
+}
+
+// useAsset sets the version/edition of the release asset to use for the
+// current directory, caching the asset if it is not already cached. If ref
+// is not empty, it is the reference to the alias of the version/edition
+// (e.g. @production), which is written to the dot file instead.
+func useAsset(asset *repository.Asset, ref string) error {
+	exists, err := helpers.Exists(asset.DirPath(app.CacheDirPath))
 	if err != nil {
 		return err
 	}
@@ -103,14 +191,17 @@ func use(version string) error {
 	if exists {
 		fmt.Printf("Using %s/%s from cache.\n", asset.Tag, asset.Edition)
 	} else {
-		err := downloadAndCache(asset)
+		err := downloadAndCache(os.Stdout, asset)
 		if err != nil {
 			return err
 		}
 	}
 
-	dm := dotfile.NewManager(app.DotFilePath, app.DotFileName, app.Name, config.DefaultEdition)
-	err = dm.Write(asset.Tag + "/" + asset.Edition)
+	if ref == "" {
+		ref = asset.Tag + "/" + asset.Edition
+	}
+	dm := newDotFileManager()
+	err = dm.Write(ref)
 	if err != nil {
 		return err
 	}
@@ -118,11 +209,37 @@ func use(version string) error {
 	return nil
 }
 
-// downloadAndCache downloads and extracts the release asset.
+// useDotFileVersion caches buildID, read from the file named source, for
+// platform p. If source is a version file written by another tool, or the
+// session version environment variable, the dot file is not created, so that
+// the dot file is not changed by a pin that it does not contain.
+func useDotFileVersion(buildID, source string, p repository.Platform) error {
+	if source == app.DotFileName {
+		return use(buildID, p)
+	}
+	if name, ok := links.ParseBuildID(buildID); ok {
+		path, err := linkedExecPath(name)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stat(path)
+		return err
+	}
+	return fetch(buildID, p)
+}
+
+// downloadAndCache downloads and extracts the release asset, writing progress
+// to w.
 // SetURLFromEditions must be called before this function to populate asset.ArchiveURL and asset.ArchiveExt.
-func downloadAndCache(asset *repository.Asset) error {
-	var err error
-	asset.ArchiveDirPath, err = os.MkdirTemp("", "")
+// The asset is extracted to a staging directory within the cache directory,
+// and moved to its build directory only after it has been verified, so that a
+// failed or interrupted download never leaves a partial build in the cache.
+func downloadAndCache(w io.Writer, asset *repository.Asset) error {
+	err := os.MkdirAll(app.StagingDirPath, 0o755)
+	if err != nil {
+		return err
+	}
+	asset.ArchiveDirPath, err = os.MkdirTemp(app.StagingDirPath, "")
 	if err != nil {
 		return err
 	}
@@ -134,37 +251,188 @@ func downloadAndCache(asset *repository.Asset) error {
 
 	client := newHTTPClient()
 
-	digest, err := downloadAsset(asset, client)
-	if err != nil {
-		return err
+	// Determine the expected digest before downloading so that a download
+	// that fails verification can be retried from the next source.
+	archiveFilename := path.Base(asset.ArchiveURL)
+	expected := asset.Checksum
+	if expected == "" && asset.ChecksumsURL != "" {
+		expected, err = fetchExpectedChecksumFromSources(asset, client, archiveFilename)
+		if err != nil {
+			return err
+		}
+	}
+	if expected == "" {
+		fmt.Fprintf(os.Stderr, "Warning: no checksums file found for %s; skipping integrity check\n", asset.Tag)
 	}
 
-	if asset.ChecksumsURL != "" {
-		archiveFilename := path.Base(asset.ArchiveURL)
-		expected, err := fetchExpectedChecksum(client, asset.ChecksumsURL, archiveFilename)
+	// Stream gzipped tarballs directly into the staging directory, unless
+	// the archive itself must be retained.
+	var digest string
+	if asset.ArchiveExt == "tar.gz" && !config.KeepArchives {
+		digest, err = streamAsset(w, asset, client, expected)
 		if err != nil {
 			return err
 		}
-		if digest != expected {
-			return fmt.Errorf("checksum mismatch for %s: got %s, expected %s", archiveFilename, digest, expected)
-		}
 	} else {
-		fmt.Fprintf(os.Stderr, "Warning: no checksums file found for %s; skipping integrity check\n", asset.Tag)
+		digest, err = downloadAsset(w, asset, client, expected)
+		if err != nil {
+			return err
+		}
+
+		if config.KeepArchives && expected != "" {
+			err = helpers.CopyFile(asset.ArchiveFilePath, filepath.Join(app.ArchivesDirPath, archiveFilename))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = archive.ExtractWithLimits(asset.ArchiveFilePath, asset.ArchiveDirPath, true, extractFilter(asset), extractLimits())
+		if err != nil {
+			return fmt.Errorf("unable to extract %s: %w", archiveFilename, limitErrorWithHint(err))
+		}
+	}
+
+	// A Debian package installs the executable to usr/local/bin.
+	extractedDirPath := asset.ArchiveDirPath
+	if asset.ArchiveExt == "deb" {
+		extractedDirPath = filepath.Join(extractedDirPath, "usr", "local", "bin")
 	}
 
-	err = archive.Extract(asset.ArchiveFilePath, asset.ArchiveDirPath, true)
+	err = verifyExecutable(asset, extractedDirPath)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to cache %s/%s: %w", asset.Tag, asset.Edition, err)
+	}
+
+	// Confirm that the executable runs on this machine before caching it.
+	// Builds for other platforms cannot be run.
+	var hugoVersion string
+	if config.SmokeTest && asset.TargetPlatform() == repository.HostPlatform() {
+		hugoVersion, err = smokeTest(asset, extractedDirPath)
+		if err != nil {
+			return fmt.Errorf("unable to cache %s/%s: %w: to skip this check, set the smokeTest configuration value to false: see %s", asset.Tag, asset.Edition, err, viper.ConfigFileUsed())
+		}
 	}
 
-	err = helpers.CopyDirectoryContent(asset.ArchiveDirPath, filepath.Join(app.CacheDirPath, asset.Tag, asset.Edition))
+	m, err := cache.NewManifest(extractedDirPath, asset.Tag, asset.Edition)
+	if err != nil {
+		return err
+	}
+	m.OS = asset.TargetPlatform().OS
+	m.Arch = asset.TargetPlatform().Arch
+	m.ArchiveURL = asset.ArchiveURL
+	m.ArchiveSHA256 = digest
+	m.Verified = expected != ""
+	m.HugoVersion = hugoVersion
+
+	err = m.Write(extractedDirPath)
 	if err != nil {
 		return err
 	}
 
+	return cache.CommitBuild(extractedDirPath, asset.DirPath(app.CacheDirPath))
+}
+
+// extractFilter returns the filter that selects the files to extract from
+// the release archive of asset a: the executable and its license files.
+func extractFilter(a *repository.Asset) archive.Filter {
+	return archive.ExecutableFilter(a.ExecName, true)
+}
+
+// verifyExecutable returns an error unless dir, the extracted release archive
+// of asset a, contains its executable as a regular file that, for a platform
+// other than Windows, has execute permission.
+func verifyExecutable(a *repository.Asset, dir string) error {
+	archiveFilename := path.Base(a.ArchiveURL)
+	fi, err := os.Lstat(filepath.Join(dir, a.ExecName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s does not contain the %s executable", archiveFilename, a.ExecName)
+	}
+	if err != nil {
+		return err
+	}
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("%s in %s is not a regular file", a.ExecName, archiveFilename)
+	}
+	if a.TargetPlatform().OS != "windows" && fi.Mode().Perm()&0o111 == 0 {
+		return fmt.Errorf("%s in %s is not executable", a.ExecName, archiveFilename)
+	}
 	return nil
 }
 
+// smokeTestTimeout is the maximum time to wait for the version command of a
+// newly extracted executable.
+const smokeTestTimeout = 30 * time.Second
+
+// smokeTest runs the executable of asset a, extracted to dir, with the version
+// command, and returns the first line of its output. It returns an error if
+// the executable does not run, or if it reports a tag or edition other than
+// that of a.
+func smokeTest(a *repository.Asset, dir string) (string, error) {
+	bi, line, err := runVersion(filepath.Join(dir, a.ExecName))
+	if err != nil {
+		return "", err
+	}
+	if bi.Tag != a.Tag || bi.Edition != a.Edition {
+		return "", fmt.Errorf("%s version: the executable reports %s/%s", a.ExecName, bi.Tag, bi.Edition)
+	}
+	return line, nil
+}
+
+// runVersion runs the executable at execPath with the version command, in the
+// directory containing it, and returns its build information and the first
+// line of its output.
+func runVersion(execPath string) (repository.BuildInfo, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), smokeTestTimeout)
+	defer cancel()
+
+	name := filepath.Base(execPath)
+	cmd := exec.CommandContext(ctx, execPath, "version")
+	cmd.Dir = filepath.Dir(execPath)
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		err = fmt.Errorf("timed out after %s", smokeTestTimeout)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("%s version: %s", name, err)
+		if line := firstLine(string(out)); line != "" {
+			msg += ": " + line
+		}
+		if hint := smokeTestHint(err, string(out)); hint != "" {
+			msg += ": " + hint
+		}
+		return repository.BuildInfo{}, "", errors.New(msg)
+	}
+
+	bi, err := repository.ParseBuildInfo(string(out))
+	if err != nil {
+		return repository.BuildInfo{}, "", fmt.Errorf("%s version: %w", name, err)
+	}
+	return bi, firstLine(string(out)), nil
+}
+
+// smokeTestHint returns the likely cause of the failure, with error err and
+// output out, of the version command of a newly extracted executable, or an
+// empty string if the cause is unknown.
+func smokeTestHint(err error, out string) string {
+	switch {
+	case strings.Contains(out, "GLIBC_"):
+		return "the C library on this machine is older than the executable requires"
+	case errors.Is(err, syscall.ENOEXEC):
+		return "the executable was built for a different operating system or architecture"
+	case errors.Is(err, fs.ErrNotExist):
+		return "the dynamic loader that the executable requires is missing, as on Linux distributions that use musl instead of glibc"
+	case errors.Is(err, fs.ErrPermission):
+		return "the cache directory may be on a file system mounted with the noexec option"
+	}
+	return ""
+}
+
+// firstLine returns the first line of s, without surrounding white space.
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(s)
+}
+
 // newHTTPClient returns an HTTP client with a connection/header timeout but no
 // overall timeout — Hugo release assets are large and streaming must not be
 // interrupted once it starts. It clones http.DefaultTransport so that proxy
@@ -184,13 +452,148 @@ func newHTTPClient() *http.Client {
 		}
 	}
 	transport.ResponseHeaderTimeout = 30 * time.Second
+	transport.RegisterProtocol("file", fileTransport{})
 	return &http.Client{Transport: transport}
 }
 
+// fileTransport is an http.RoundTripper that serves file URLs from the local
+// file system, so that release assets and indexes in a local directory can be
+// read with the same client used for remote ones.
+type fileTransport struct{}
+
+// RoundTrip implements http.RoundTripper.
+func (fileTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := &http.Response{
+		Proto:      "HTTP/1.0",
+		ProtoMajor: 1,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+		Request:    req,
+	}
+
+	f, err := os.Open(helpers.FileURLToPath(req.URL))
+	if errors.Is(err, fs.ErrNotExist) {
+		resp.StatusCode = http.StatusNotFound
+		resp.Status = "404 Not Found"
+		return resp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if fi.IsDir() {
+		f.Close()
+		return nil, fmt.Errorf("%s is a directory", req.URL)
+	}
+
+	resp.StatusCode = http.StatusOK
+	resp.Status = "200 OK"
+	resp.ContentLength = fi.Size()
+	resp.Body = f
+	return resp, nil
+}
+
 // downloadAsset downloads the release asset and returns its SHA-256 hex digest.
-func downloadAsset(a *repository.Asset, client *http.Client) (digest string, retErr error) {
-	// Create the file.
+// It tries each source in turn as described in trySources.
+func downloadAsset(w io.Writer, a *repository.Asset, client *http.Client, expected string) (string, error) {
 	a.ArchiveFilePath = filepath.Join(a.ArchiveDirPath, "hugo."+a.ArchiveExt)
+
+	return trySources(a, expected, func(u string) (string, error) {
+		return downloadFile(w, a, client, u)
+	})
+}
+
+// streamAsset downloads the release asset, a gzipped tarball, extracting it
+// to a.ArchiveDirPath as it is received, and returns its SHA-256 hex digest.
+// It tries each source in turn as described in trySources, emptying
+// a.ArchiveDirPath before each attempt. An error extracting a download that
+// passed verification is returned without trying the next source, because
+// every source would produce the same result.
+func streamAsset(w io.Writer, a *repository.Asset, client *http.Client, expected string) (string, error) {
+	var extractErr error
+	digest, err := trySources(a, expected, func(u string) (string, error) {
+		err := os.RemoveAll(a.ArchiveDirPath)
+		if err != nil {
+			return "", err
+		}
+		err = os.Mkdir(a.ArchiveDirPath, 0o700)
+		if err != nil {
+			return "", err
+		}
+
+		var digest string
+		digest, extractErr, err = streamFile(w, a, client, u)
+		return digest, err
+	})
+	if err != nil {
+		return "", err
+	}
+	if extractErr != nil {
+		return "", fmt.Errorf("unable to extract %s: %w", path.Base(a.ArchiveURL), limitErrorWithHint(extractErr))
+	}
+
+	return digest, nil
+}
+
+// trySources calls download with the URL of each source of the release
+// asset in turn, returning the SHA-256 hex digest of the first successful
+// download. It tries each peer, then each mirror, before falling back to the
+// origin. If expected is not empty, a download whose digest does not match is
+// rejected in favor of the next source. Peers are skipped if expected is empty
+// because their downloads cannot be verified.
+func trySources(a *repository.Asset, expected string, download func(u string) (string, error)) (string, error) {
+	urls, err := sourceURLs(a, a.ArchiveURL)
+	if err != nil {
+		return "", err
+	}
+	if expected != "" {
+		urls = append(peerURLs(a.ArchiveURL), urls...)
+	}
+
+	for i, u := range urls {
+		digest, err := download(u)
+		if err == nil && expected != "" && digest != expected {
+			err = fmt.Errorf("checksum mismatch for %s: got %s, expected %s", path.Base(a.ArchiveURL), digest, expected)
+		}
+		if err == nil {
+			return digest, nil
+		}
+		if i == len(urls)-1 {
+			return "", err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: unable to download %s: %s; trying next source\n", u, err)
+	}
+
+	return "", fmt.Errorf("no download source for %s", a.ArchiveURL)
+}
+
+// peerURLs returns the URL of the release archive at u on each configured
+// peer. A peer serves release archives by file name from its base URL.
+func peerURLs(u string) []string {
+	if strings.HasPrefix(u, "file:") {
+		return nil
+	}
+	name := &url.URL{Path: path.Base(u)}
+	var urls []string
+	for _, p := range config.Peers {
+		base, err := url.Parse(strings.TrimSuffix(p, "/") + "/")
+		if err != nil {
+			continue // validated by initConfig
+		}
+		urls = append(urls, base.ResolveReference(name).String())
+	}
+	return urls
+}
+
+// downloadFile downloads the release asset from url to a.ArchiveFilePath and
+// returns its SHA-256 hex digest, writing progress to w.
+func downloadFile(w io.Writer, a *repository.Asset, client *http.Client, url string) (digest string, retErr error) {
+	// Create the file.
 	out, err := os.Create(a.ArchiveFilePath)
 	if err != nil {
 		return "", err
@@ -201,52 +604,116 @@ func downloadAsset(a *repository.Asset, client *http.Client) (digest string, ret
 		}
 	}()
 
-	resp, err := client.Get(a.ArchiveURL)
+	resp, err := client.Get(url)
 	if err != nil {
 		return "", err
 	}
-	fmt.Printf("Downloading %s/%s... ", a.Tag, a.Edition)
 	defer resp.Body.Close()
 
 	// Check server response.
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("bad status: %s", resp.Status)
 	}
+	fmt.Fprintf(w, "Downloading %s/%s... ", a.Tag, a.Edition)
 
 	// Write the body to file, computing SHA-256 as we go.
 	h := sha256.New()
 	_, err = io.Copy(out, io.TeeReader(resp.Body, h))
 	if err != nil {
+		fmt.Fprintf(w, "failed.\n")
 		return "", err
 	}
-	fmt.Printf("done.\n")
+	fmt.Fprintf(w, "done.\n")
 
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
-// fetchExpectedChecksum downloads the checksums file and returns the expected
-// SHA-256 hex digest for the named archive file.
-func fetchExpectedChecksum(client *http.Client, checksumsURL, archiveFilename string) (string, error) {
-	resp, err := client.Get(checksumsURL)
+// streamFile downloads the release asset, a gzipped tarball, from url,
+// extracting it to a.ArchiveDirPath as it is received. The body is read
+// through a SHA-256 hash, then decompressed and extracted, in a single pass.
+// It returns the SHA-256 hex digest of the entire download and any error
+// extracting it; the caller must discard the extracted files unless the
+// digest is verified. err is returned if the download itself fails. Progress
+// is written to w.
+func streamFile(w io.Writer, a *repository.Asset, client *http.Client, url string) (digest string, extractErr, err error) {
+	resp, err := client.Get(url)
 	if err != nil {
-		return "", fmt.Errorf("downloading checksums file: %w", err)
+		return "", nil, err
 	}
 	defer resp.Body.Close()
 
+	// Check server response.
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("downloading checksums file: bad status: %s", resp.Status)
+		return "", nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+	fmt.Fprintf(w, "Downloading %s/%s... ", a.Tag, a.Edition)
+
+	h := sha256.New()
+	body := io.TeeReader(resp.Body, h)
+	extractErr = archive.ExtractTarGZReader(body, a.ArchiveDirPath, extractFilter(a), extractLimits())
+
+	// Read the remainder of the body, which follows the end of the tarball
+	// or an extraction error, so that the digest covers the entire download.
+	_, err = io.Copy(io.Discard, body)
+	if err != nil {
+		fmt.Fprintf(w, "failed.\n")
+		return "", nil, err
+	}
+	fmt.Fprintf(w, "done.\n")
+
+	return hex.EncodeToString(h.Sum(nil)), extractErr, nil
+}
+
+// sourceURLs returns the URLs from which to download u on behalf of asset a,
+// in the order they should be tried: the configured mirrors followed by the
+// origin. Mirrors are not applied to file URLs.
+func sourceURLs(a *repository.Asset, u string) ([]string, error) {
+	if strings.HasPrefix(u, "file:") {
+		return []string{u}, nil
+	}
+	return mirror.URLs(config.Mirrors, u, mirror.Data{
+		Tag:     a.Tag,
+		Edition: a.Edition,
+		OS:      a.TargetPlatform().OS,
+		Arch:    a.TargetPlatform().Arch,
+	})
+}
+
+// fetchExpectedChecksumFromSources returns the expected SHA-256 hex digest for
+// the named archive file, trying each mirror of a.ChecksumsURL in turn before
+// falling back to the origin.
+func fetchExpectedChecksumFromSources(a *repository.Asset, client *http.Client, archiveFilename string) (string, error) {
+	urls, err := sourceURLs(a, a.ChecksumsURL)
+	if err != nil {
+		return "", err
 	}
 
-	scanner := bufio.NewScanner(resp.Body)
-	for scanner.Scan() {
-		fields := strings.Fields(scanner.Text())
-		if len(fields) == 2 && fields[1] == archiveFilename {
-			return fields[0], nil
+	for i, u := range urls {
+		expected, err := fetchExpectedChecksum(client, u, archiveFilename)
+		if err == nil {
+			return expected, nil
 		}
+		if i == len(urls)-1 {
+			return "", err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %s; trying next source\n", err)
 	}
-	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("reading checksums file: %w", err)
+
+	return "", fmt.Errorf("no download source for %s", a.ChecksumsURL)
+}
+
+// fetchExpectedChecksum downloads the checksums file and returns the expected
+// SHA-256 hex digest for the named archive file.
+func fetchExpectedChecksum(client *http.Client, checksumsURL, archiveFilename string) (string, error) {
+	checksums, err := repository.FetchChecksums(client, checksumsURL)
+	if err != nil {
+		return "", err
+	}
+
+	expected, ok := checksums[archiveFilename]
+	if !ok {
+		return "", fmt.Errorf("no checksum found for %s in checksums file", archiveFilename)
 	}
 
-	return "", fmt.Errorf("no checksum found for %s in checksums file", archiveFilename)
+	return expected, nil
 }
@@ -17,13 +17,24 @@ limitations under the License.
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/jmooring/hvm/archive"
 	"github.com/jmooring/hvm/cache"
+	"github.com/jmooring/hvm/libc"
+	"github.com/jmooring/hvm/mirror"
 	"github.com/jmooring/hvm/repository"
 )
 
@@ -159,11 +170,426 @@ func TestDownloadAndCache_ChecksumMismatch(t *testing.T) {
 		Edition:      "standard",
 	}
 
-	err := downloadAndCache(asset)
+	useTempCache(t)
+	err := downloadAndCache(io.Discard, asset)
 	if err == nil {
 		t.Fatal("expected checksum mismatch error, got nil")
 	}
 	if !strings.Contains(err.Error(), "checksum mismatch") {
 		t.Fatalf("want 'checksum mismatch' error, got: %v", err)
 	}
+	if _, err := os.Stat(asset.DirPath(app.CacheDirPath)); !os.IsNotExist(err) {
+		t.Fatal("downloadAndCache cached a build that failed verification")
+	}
+	assertEmptyDir(t, app.StagingDirPath)
+}
+
+// TestDownloadAndCache_Stream verifies that a gzipped tarball is extracted as
+// it is downloaded, that a download rejected by verification leaves nothing
+// behind, and that the verified build is moved from the staging directory to
+// the cache.
+func TestDownloadAndCache_Stream(t *testing.T) {
+	const archiveFile = "hugo_0.153.0_linux-amd64.tar.gz"
+
+	good := tarGZBytes(t, map[string]string{"hugo": "hugo v0.153.0", "LICENSE": "license"})
+	tampered := tarGZBytes(t, map[string]string{"hugo": "tampered", "LICENSE.evil": "evil"})
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + archiveFile:
+			_, _ = w.Write(good)
+		case "/checksums":
+			_, _ = fmt.Fprintf(w, "%s  %s\n", digest(string(good)), archiveFile)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer origin.Close()
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(tampered)
+	}))
+	defer bad.Close()
+
+	saved := config.Mirrors
+	defer func() { config.Mirrors = saved }()
+	config.Mirrors = []mirror.Rule{{Template: bad.URL + "/{{.File}}"}}
+
+	useTempCache(t)
+	asset := &repository.Asset{
+		ArchiveURL:   origin.URL + "/" + archiveFile,
+		ChecksumsURL: origin.URL + "/checksums",
+		ArchiveExt:   "tar.gz",
+		ExecName:     "hugo",
+		Tag:          "v0.153.0",
+		Edition:      "standard",
+	}
+	if err := downloadAndCache(io.Discard, asset); err != nil {
+		t.Fatalf("downloadAndCache error: %v", err)
+	}
+
+	buildDirPath := asset.DirPath(app.CacheDirPath)
+	got, err := os.ReadFile(filepath.Join(buildDirPath, "hugo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hugo v0.153.0" {
+		t.Fatalf("executable: want %q got %q", "hugo v0.153.0", got)
+	}
+	if _, err := os.Stat(filepath.Join(buildDirPath, "LICENSE.evil")); !os.IsNotExist(err) {
+		t.Fatal("downloadAndCache kept a file from a download that failed verification")
+	}
+
+	m, err := cache.ReadManifest(buildDirPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.ArchiveSHA256 != digest(string(good)) || !m.Verified {
+		t.Fatalf("manifest: want verified digest %s got %s (verified %t)", digest(string(good)), m.ArchiveSHA256, m.Verified)
+	}
+	if err := m.Verify(buildDirPath); err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	assertEmptyDir(t, app.StagingDirPath)
+}
+
+// TestVerifyExecutable verifies that verifyExecutable rejects an extracted
+// release archive without a usable executable.
+func TestVerifyExecutable(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(dir string) error
+		goos  string
+		want  string
+	}{
+		{"ok", func(dir string) error { return os.WriteFile(filepath.Join(dir, "hugo"), nil, 0o755) }, "linux", ""},
+		{"missing", func(dir string) error { return nil }, "linux", "does not contain the hugo executable"},
+		{"directory", func(dir string) error { return os.Mkdir(filepath.Join(dir, "hugo"), 0o755) }, "linux", "is not a regular file"},
+		{"notExecutable", func(dir string) error { return os.WriteFile(filepath.Join(dir, "hugo"), nil, 0o644) }, "linux", "is not executable"},
+		{"windows", func(dir string) error { return os.WriteFile(filepath.Join(dir, "hugo"), nil, 0o644) }, "windows", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.name == "notExecutable" && runtime.GOOS == "windows" {
+				t.Skip("execute permission is not represented on Windows")
+			}
+			dir := t.TempDir()
+			if err := tt.setup(dir); err != nil {
+				t.Fatal(err)
+			}
+			asset := &repository.Asset{
+				ArchiveURL: "https://example.org/hugo_0.153.0_linux-amd64.tar.gz",
+				ExecName:   "hugo",
+				Platform:   repository.Platform{OS: tt.goos, Arch: "amd64"},
+			}
+			err := verifyExecutable(asset, dir)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("verifyExecutable error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("verifyExecutable: want error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestSmokeTest verifies that smokeTest reports the likely cause of an
+// executable that does not run.
+func TestSmokeTest(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test executables are shell scripts")
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		perm    os.FileMode
+		want    string
+	}{
+		{"ok", "#!/bin/sh\necho 'hugo v0.153.0-0000000+extended linux/amd64 BuildDate=unknown'\n", 0o755, ""},
+		{"glibc", "#!/bin/sh\necho \"hugo: version \\`GLIBC_2.32' not found (required by hugo)\" >&2\nexit 1\n", 0o755, "exit status 1: hugo: version `GLIBC_2.32' not found (required by hugo): the C library on this machine is older"},
+		{"format", "\x7fELF\x00\x00\x00\x00garbage", 0o755, "exec format error: the executable was built for a different"},
+		{"permission", "#!/bin/sh\n", 0o644, "permission denied: the cache directory may be on a file system mounted with the noexec option"},
+		{"edition", "#!/bin/sh\necho 'hugo v0.153.0-0000000 linux/amd64 BuildDate=unknown'\n", 0o755, "the executable reports v0.153.0/standard"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "hugo"), []byte(tt.content), tt.perm); err != nil {
+				t.Fatal(err)
+			}
+			asset := &repository.Asset{ExecName: "hugo", Tag: "v0.153.0", Edition: "extended"}
+			got, err := smokeTest(asset, dir)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("smokeTest error: %v", err)
+				}
+				if !strings.HasPrefix(got, "hugo v0.153.0-0000000+extended") {
+					t.Fatalf("smokeTest: unexpected output %q", got)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("smokeTest: want error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestCheckLibc verifies that checkLibc refuses editions that are dynamically
+// linked against glibc on a musl host without a glibc compatibility layer.
+func TestCheckLibc(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the C library is only checked on Linux")
+	}
+	saved := detectLibc
+	defer func() { detectLibc = saved }()
+
+	musl := libc.Info{Family: libc.Musl, Version: "1.2.4"}
+	gcompat := libc.Info{Family: libc.Musl, Version: "1.2.4", GlibcLoader: true}
+	glibc := libc.Info{Family: libc.Glibc, Version: "2.36", GlibcLoader: true}
+	host := repository.HostPlatform()
+	other := repository.Platform{OS: "linux", Arch: "s390x"}
+	if host == other {
+		other.Arch = "amd64"
+	}
+
+	tests := []struct {
+		name     string
+		info     libc.Info
+		edition  string
+		platform repository.Platform
+		wantErr  bool
+	}{
+		{"muslExtended", musl, "extended", host, true},
+		{"muslWithdeploy", musl, "withdeploy", host, true},
+		{"muslStandard", musl, "standard", host, false},
+		{"muslOtherPlatform", musl, "extended", other, false},
+		{"gcompat", gcompat, "extended_withdeploy", host, false},
+		{"glibc", glibc, "extended", host, false},
+		{"unknown", libc.Info{}, "extended", host, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detectLibc = func() libc.Info { return tt.info }
+			asset := &repository.Asset{Edition: tt.edition, Platform: tt.platform}
+			err := checkLibc(asset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkLibc: wantErr %t, got %v", tt.wantErr, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "use the standard edition") {
+				t.Fatalf("checkLibc: error does not suggest the standard edition: %v", err)
+			}
+		})
+	}
+}
+
+// TestDownloadAsset_MirrorFallback verifies that downloadAsset tries each
+// mirror in order and falls back to the origin when every mirror fails.
+func TestDownloadAsset_MirrorFallback(t *testing.T) {
+	const archiveFile = "hugo_0.153.0_linux-amd64.tar.gz"
+	const content = "fake archive content"
+
+	var requests []string
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, "origin")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(content))
+	}))
+	defer origin.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, "broken")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer broken.Close()
+
+	saved := config.Mirrors
+	defer func() { config.Mirrors = saved }()
+	config.Mirrors = []mirror.Rule{
+		{Prefix: origin.URL + "/", Replace: broken.URL + "/"},
+		{Template: broken.URL + "/{{.Tag}}/{{.File}}"},
+	}
+
+	asset := &repository.Asset{
+		ArchiveDirPath: t.TempDir(),
+		ArchiveURL:     origin.URL + "/" + archiveFile,
+		ArchiveExt:     "tar.gz",
+		Tag:            "v0.153.0",
+		Edition:        "standard",
+	}
+
+	digest, err := downloadAsset(io.Discard, asset, &http.Client{}, "")
+	if err != nil {
+		t.Fatalf("downloadAsset error: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	if want := hex.EncodeToString(sum[:]); digest != want {
+		t.Fatalf("digest: want %s got %s", want, digest)
+	}
+	if want := []string{"broken", "broken", "origin"}; !slices.Equal(requests, want) {
+		t.Fatalf("requests: want %v got %v", want, requests)
+	}
+	got, err := os.ReadFile(asset.ArchiveFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != content {
+		t.Fatalf("archive content: want %q got %q", content, got)
+	}
+}
+
+// TestDownloadAsset_Peers verifies that downloadAsset prefers peers, rejects a
+// peer download that does not match the expected digest, and skips peers when
+// no digest is expected.
+func TestDownloadAsset_Peers(t *testing.T) {
+	const archiveFile = "hugo_0.153.0_linux-amd64.tar.gz"
+	const content = "fake archive content"
+
+	var requests []string
+	server := func(name, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, name+r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+	origin := server("origin", content)
+	defer origin.Close()
+	good := server("good", content)
+	defer good.Close()
+	bad := server("bad", "tampered")
+	defer bad.Close()
+
+	saved := config.Peers
+	defer func() { config.Peers = saved }()
+
+	tests := []struct {
+		name     string
+		peers    []string
+		expected string
+		want     []string
+	}{
+		{"peer", []string{good.URL}, digest(content), []string{"good/" + archiveFile}},
+		{"badPeer", []string{bad.URL + "/", good.URL}, digest(content), []string{"bad/" + archiveFile, "good/" + archiveFile}},
+		{"unverifiable", []string{good.URL}, "", []string{"origin/" + archiveFile}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			config.Peers = tt.peers
+			asset := &repository.Asset{
+				ArchiveDirPath: t.TempDir(),
+				ArchiveURL:     origin.URL + "/" + archiveFile,
+				ArchiveExt:     "tar.gz",
+				Tag:            "v0.153.0",
+				Edition:        "standard",
+			}
+			got, err := downloadAsset(io.Discard, asset, &http.Client{}, tt.expected)
+			if err != nil {
+				t.Fatalf("downloadAsset error: %v", err)
+			}
+			if got != digest(content) {
+				t.Fatalf("digest: want %s got %s", digest(content), got)
+			}
+			if !slices.Equal(requests, tt.want) {
+				t.Fatalf("requests: want %v got %v", tt.want, requests)
+			}
+		})
+	}
+}
+
+// BenchmarkDownloadAsset compares downloading a gzipped tarball to a file and
+// then extracting it with extracting it as it is downloaded.
+func BenchmarkDownloadAsset(b *testing.B) {
+	const archiveFile = "hugo_0.153.0_linux-amd64.tar.gz"
+
+	// Use incompressible content, comparable in size to a Hugo executable.
+	content := make([]byte, 64<<20)
+	_, _ = rand.NewChaCha8([32]byte{}).Read(content)
+	data := tarGZBytes(b, map[string]string{"hugo": string(content)})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer ts.Close()
+
+	download := map[string]func(a *repository.Asset) error{
+		"file": func(a *repository.Asset) error {
+			if _, err := downloadAsset(io.Discard, a, &http.Client{}, ""); err != nil {
+				return err
+			}
+			return archive.ExtractWithLimits(a.ArchiveFilePath, a.ArchiveDirPath, true, nil, archive.DefaultLimits)
+		},
+		"stream": func(a *repository.Asset) error {
+			_, err := streamAsset(io.Discard, a, &http.Client{}, "")
+			return err
+		},
+	}
+	for _, name := range []string{"file", "stream"} {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for b.Loop() {
+				asset := &repository.Asset{
+					ArchiveDirPath: b.TempDir(),
+					ArchiveURL:     ts.URL + "/" + archiveFile,
+					ArchiveExt:     "tar.gz",
+					Tag:            "v0.153.0",
+					Edition:        "standard",
+				}
+				if err := download[name](asset); err != nil {
+					b.Fatal(err)
+				}
+				if err := os.RemoveAll(asset.ArchiveDirPath); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// useTempCache sets the application cache and staging directories to a
+// temporary directory for the duration of the test.
+func useTempCache(t *testing.T) {
+	t.Helper()
+	saved := app
+	t.Cleanup(func() { app = saved })
+	app.CacheDirPath = t.TempDir()
+	app.StagingDirPath = filepath.Join(app.CacheDirPath, app.StagingDirName)
+}
+
+// assertEmptyDir fails the test if dir contains any entries.
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("%s is not empty: %v", dir, entries)
+	}
+}
+
+// tarGZBytes returns a gzipped tarball containing files, keyed by name.
+func tarGZBytes(tb testing.TB, files map[string]string) []byte {
+	tb.Helper()
+	name := filepath.Join(tb.TempDir(), "archive.tar.gz")
+	if err := writeTarGZ(name, files); err != nil {
+		tb.Fatal(err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return data
+}
+
+// digest returns the SHA-256 hex digest of s.
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jmooring/hvm/aliases"
+	"github.com/jmooring/hvm/repository"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+)
+
+// versionAliasCmd represents the alias command.
+var versionAliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage named aliases of versions, such as production or next",
+	Long: `Manage named aliases of versions, such as "production" or "next". Refer to an
+alias as @<name> wherever you would specify a version/edition, including the
+` + app.DotFileName + ` file:
+
+  ` + app.Name + ` alias set production v0.150.1/extended
+  ` + app.Name + ` use @production
+
+The alias is resolved each time it is used, so setting it to another
+version/edition updates every project that refers to it. Aliases are stored in
+the configuration directory.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// init registers the alias command with the root command.
+func init() {
+	rootCmd.AddCommand(versionAliasCmd)
+}
+
+// resolveAlias returns the version/edition of the alias referenced by version
+// (e.g. @production), or version if it does not reference an alias.
+func resolveAlias(version string) (string, error) {
+	if !strings.HasPrefix(version, aliases.Prefix) {
+		return version, nil
+	}
+	name, ok := aliases.ParseRef(version)
+	if !ok {
+		return "", aliases.ValidateName(strings.TrimPrefix(version, aliases.Prefix))
+	}
+
+	r, err := aliases.Read(app.AliasesFilePath)
+	if err != nil {
+		return "", err
+	}
+	buildID, ok := r[name]
+	if !ok {
+		return "", fmt.Errorf("alias %s is not defined: run \"%s alias set %s <version>\" to define it", version, app.Name, name)
+	}
+	return buildID, nil
+}
+
+// aliasTarget returns the version/edition to which an alias may refer for
+// version, which is a version (e.g. v0.150.1 or 0.150.1) or a version/edition.
+// If version does not include an edition, the default edition is used.
+func aliasTarget(version string) (string, error) {
+	tag, edition, ok := strings.Cut(version, "/")
+	if !ok {
+		edition = config.DefaultEdition
+	}
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	if !semver.IsValid(tag) || !slices.Contains(repository.ValidEditions, edition) {
+		return "", fmt.Errorf("invalid version %q: must be a version or version/edition (e.g. v0.150.1/extended)", version)
+	}
+	return tag + "/" + edition, nil
+}
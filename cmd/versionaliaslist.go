@@ -0,0 +1,59 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jmooring/hvm/aliases"
+	"github.com/spf13/cobra"
+)
+
+// versionAliasListCmd represents the alias list command.
+var versionAliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases",
+	Long:  "List aliases and the version/edition to which each refers.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := listAliases()
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the list command with the alias command.
+func init() {
+	versionAliasCmd.AddCommand(versionAliasListCmd)
+}
+
+// listAliases displays each alias and the version/edition to which it refers.
+func listAliases() error {
+	r, err := aliases.Read(app.AliasesFilePath)
+	if err != nil {
+		return err
+	}
+	if len(r) == 0 {
+		fmt.Printf("No aliases are defined: run \"%s alias set <name> <version>\" to define one.\n", app.Name)
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(r)) {
+		fmt.Printf("%s: %s\n", aliases.Ref(name), r[name])
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmooring/hvm/aliases"
+	"github.com/spf13/cobra"
+)
+
+// versionAliasRmCmd represents the alias rm command.
+var versionAliasRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove an alias",
+	Long: `Remove an alias. Projects whose ` + app.DotFileName + ` file refers to the alias cannot
+be used until you set it again.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := removeAlias(args[0])
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the rm command with the alias command.
+func init() {
+	versionAliasCmd.AddCommand(versionAliasRmCmd)
+}
+
+// removeAlias removes the alias name.
+func removeAlias(name string) error {
+	r, err := aliases.Read(app.AliasesFilePath)
+	if err != nil {
+		return err
+	}
+	if _, ok := r[name]; !ok {
+		return fmt.Errorf("alias %s is not defined", aliases.Ref(name))
+	}
+	delete(r, name)
+	err = r.Write(app.AliasesFilePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed %s.\n", aliases.Ref(name))
+
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmooring/hvm/aliases"
+	"github.com/spf13/cobra"
+)
+
+// versionAliasSetCmd represents the alias set command.
+var versionAliasSetCmd = &cobra.Command{
+	Use:   "set <name> <version>",
+	Short: "Set an alias to a version/edition",
+	Long: `Set an alias to a version/edition, creating the alias if it does not exist.
+If you do not specify an edition, the default edition is used:
+
+  ` + app.Name + ` alias set production v0.150.1/extended
+  ` + app.Name + ` alias set next 0.153.0`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := setAlias(args[0], args[1])
+		cobra.CheckErr(err)
+	},
+}
+
+// init registers the set command with the alias command.
+func init() {
+	versionAliasCmd.AddCommand(versionAliasSetCmd)
+}
+
+// setAlias sets the alias name to version.
+func setAlias(name, version string) error {
+	err := aliases.ValidateName(name)
+	if err != nil {
+		return err
+	}
+	buildID, err := aliasTarget(version)
+	if err != nil {
+		return err
+	}
+
+	r, err := aliases.Read(app.AliasesFilePath)
+	if err != nil {
+		return err
+	}
+	r[name] = buildID
+	err = r.Write(app.AliasesFilePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Set %s to %s.\n", aliases.Ref(name), buildID)
+
+	return nil
+}
@@ -17,8 +17,17 @@ limitations under the License.
 // Package dotfile provides operations on the application dot file.
 //
 // The dot file stores a build identifier of the form "version/edition"
-// (e.g. "v0.160.0/extended"). Files written by older versions of hvm contain
+// (e.g. "v0.160.0/extended"), "local/name" for a linked build
+// (e.g. "local/patched"), or "@name" for an alias (e.g. "@production"),
+// which is resolved by the caller. Files written by older versions of hvm contain
 // only a version string; Read migrates these automatically.
+//
+// When the current directory does not contain a dot file, Read consults an
+// ordered list of fallback files written by other tools, such as
+// .tool-versions and netlify.toml.
+//
+// Write records each build identifier written to the dot file, keyed by
+// directory, in a history file shared by all directories.
 package dotfile
 
 import (
@@ -27,9 +36,12 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 
+	"github.com/jmooring/hvm/aliases"
+	"github.com/jmooring/hvm/links"
 	"github.com/jmooring/hvm/repository"
 	"golang.org/x/mod/semver"
 )
@@ -45,36 +57,82 @@ type Manager struct {
 	filePath       string
 	fileName       string
 	appName        string
-	defaultEdition string // used when migrating files written by older hvm versions
+	defaultEdition string   // used when migrating files written by older hvm versions
+	fallbacks      []string // names of the fallback files, in order
+
+	historyFilePath string // path of the history of dot files, or empty to keep no history
 }
 
 // NewManager creates a new dotfile manager. defaultEdition is used when
 // migrating dot files written by older versions of hvm that contain only a
-// version string.
-func NewManager(filePath, fileName, appName, defaultEdition string) *Manager {
+// version string, and when a fallback file does not specify an edition.
+// fallbacks is the ordered list of fallback files to consult when the dot
+// file does not exist; each must be one of the names returned by Fallbacks.
+// historyFilePath is the path of the file in which Write records the history
+// of dot files, keyed by directory; if it is empty, no history is kept.
+func NewManager(filePath, fileName, appName, defaultEdition string, fallbacks []string, historyFilePath string) *Manager {
 	return &Manager{
-		filePath:       filePath,
-		fileName:       fileName,
-		appName:        appName,
-		defaultEdition: defaultEdition,
+		filePath:        filePath,
+		fileName:        fileName,
+		appName:         appName,
+		defaultEdition:  defaultEdition,
+		fallbacks:       fallbacks,
+		historyFilePath: historyFilePath,
 	}
 }
 
 // Read reads the build identifier ("version/edition") from the dot file in the
-// current directory, or returns an empty string if the file does not exist.
+// current directory. If the dot file does not exist, Read returns the build
+// identifier pinned by the first fallback file that pins Hugo, or an empty
+// string if none does.
 //
 // If the file contains only a version (written by an older version of hvm),
 // Read migrates it to the version/edition format, rewrites the file, and
 // prints a warning to stderr.
 func (m *Manager) Read() (string, error) {
+	buildID, _, err := m.ReadWithSource()
+	return buildID, err
+}
+
+// ReadWithSource is like Read, but also returns the name of the file that the
+// build identifier was read from, or an empty string if none.
+func (m *Manager) ReadWithSource() (buildID, source string, err error) {
 	exists, err := fileExists(m.filePath)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	if !exists {
-		return "", nil
+		return m.readFallbacks()
 	}
 
+	buildID, err = m.readDotFile()
+	if err != nil {
+		return "", "", err
+	}
+	return buildID, m.fileName, nil
+}
+
+// readFallbacks returns the build identifier pinned by the first fallback file
+// in the dot file's directory that pins Hugo, and the name of that file.
+func (m *Manager) readFallbacks() (buildID, source string, err error) {
+	dir := filepath.Dir(m.filePath)
+	for _, name := range m.fallbacks {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+		if buildID := m.readFallback(name, data); buildID != "" {
+			return buildID, name, nil
+		}
+	}
+	return "", "", nil
+}
+
+// readDotFile reads the build identifier from the dot file, which must exist.
+func (m *Manager) readDotFile() (string, error) {
 	f, err := os.Open(m.filePath)
 	if err != nil {
 		return "", err
@@ -94,6 +152,16 @@ func (m *Manager) Read() (string, error) {
 		return "", fmt.Errorf("the %s file in the current directory is empty: %s", m.fileName, theFix)
 	}
 
+	// Linked build: local/name (e.g. "local/patched").
+	if _, ok := links.ParseBuildID(dotFileContent); ok {
+		return dotFileContent, nil
+	}
+
+	// Alias: @name (e.g. "@production").
+	if _, ok := aliases.ParseRef(dotFileContent); ok {
+		return dotFileContent, nil
+	}
+
 	// New format: version/edition (e.g. "v0.160.0/extended").
 	if strings.Contains(dotFileContent, "/") {
 		parts := strings.SplitN(dotFileContent, "/", 2)
@@ -124,13 +192,21 @@ func (m *Manager) Read() (string, error) {
 	return migrated, nil
 }
 
-// Write writes the version to the dot file for the current directory.
+// Write writes the version to the dot file for the current directory, and
+// then records it in the history of the directory. Nothing is recorded if the
+// dot file cannot be written.
 func (m *Manager) Write(version string) error {
-	err := os.WriteFile(m.filePath, []byte(version), 0o644)
+	// The content being replaced is read first, so that it can be recorded
+	// if the history of the directory is empty.
+	replaced, err := m.currentEntry()
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(m.filePath, []byte(version), 0o644)
 	if err != nil {
 		return err
 	}
-	return nil
+	return m.record(version, replaced)
 }
 
 // fileExists checks if a file exists.
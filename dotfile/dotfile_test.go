@@ -24,7 +24,7 @@ import (
 )
 
 func TestNewManager(t *testing.T) {
-	m := NewManager("/tmp/.hvm", ".hvm", "hvm", "standard")
+	m := NewManager("/tmp/.hvm", ".hvm", "hvm", "standard", nil, "")
 	if m == nil {
 		t.Fatal("NewManager returned nil")
 	}
@@ -33,7 +33,7 @@ func TestNewManager(t *testing.T) {
 func TestRead_FileDoesNotExist(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, ".hvm")
-	m := NewManager(path, ".hvm", "hvm", "standard")
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
 	got, err := m.Read()
 	if err != nil {
 		t.Fatalf("Read() unexpected error: %v", err)
@@ -49,7 +49,7 @@ func TestRead_EmptyFile(t *testing.T) {
 	if err := os.WriteFile(path, []byte("\n\n"), 0o644); err != nil {
 		t.Fatalf("write: %v", err)
 	}
-	m := NewManager(path, ".hvm", "hvm", "standard")
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
 	_, err := m.Read()
 	if err == nil {
 		t.Fatal("Read() expected error for empty file")
@@ -68,7 +68,7 @@ func TestRead_InvalidFormat(t *testing.T) {
 	if err := os.WriteFile(path, []byte("1.2.3"), 0o644); err != nil {
 		t.Fatalf("write: %v", err)
 	}
-	m := NewManager(path, ".hvm", "hvm", "standard")
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
 	_, err := m.Read()
 	if err == nil {
 		t.Fatal("Read() expected error for invalid format")
@@ -84,7 +84,7 @@ func TestRead_EmptyEdition(t *testing.T) {
 	if err := os.WriteFile(path, []byte("v1.2.3/"), 0o644); err != nil {
 		t.Fatalf("write: %v", err)
 	}
-	m := NewManager(path, ".hvm", "hvm", "standard")
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
 	_, err := m.Read()
 	if err == nil {
 		t.Fatal("Read() expected error for empty edition")
@@ -103,7 +103,7 @@ func TestRead_LegacyVersionMigrated(t *testing.T) {
 	if err := os.WriteFile(path, []byte("v0.100.0"), 0o644); err != nil {
 		t.Fatalf("write: %v", err)
 	}
-	m := NewManager(path, ".hvm", "hvm", "standard")
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
 	got, err := m.Read()
 	if err != nil {
 		t.Fatalf("Read() unexpected error: %v", err)
@@ -127,7 +127,7 @@ func TestRead_LegacyVersionAboveBoundaryMigrated(t *testing.T) {
 	if err := os.WriteFile(path, []byte("v0.200.0"), 0o644); err != nil {
 		t.Fatalf("write: %v", err)
 	}
-	m := NewManager(path, ".hvm", "hvm", "extended_withdeploy")
+	m := NewManager(path, ".hvm", "hvm", "extended_withdeploy", nil, "")
 	got, err := m.Read()
 	if err != nil {
 		t.Fatalf("Read() unexpected error: %v", err)
@@ -143,7 +143,7 @@ func TestRead_ValidBuildID(t *testing.T) {
 	if err := os.WriteFile(path, []byte("  v1.2.3/extended  \n"), 0o644); err != nil {
 		t.Fatalf("write: %v", err)
 	}
-	m := NewManager(path, ".hvm", "hvm", "standard")
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
 	got, err := m.Read()
 	if err != nil {
 		t.Fatalf("Read() unexpected error: %v", err)
@@ -156,7 +156,7 @@ func TestRead_ValidBuildID(t *testing.T) {
 func TestWriteAndRead_RoundTrip(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, ".hvm")
-	m := NewManager(path, ".hvm", "hvm", "standard")
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
 	if err := m.Write("v0.54.0/extended"); err != nil {
 		t.Fatalf("Write() error: %v", err)
 	}
@@ -192,3 +192,196 @@ func TestFileExists(t *testing.T) {
 		t.Fatal("fileExists: want true got false")
 	}
 }
+
+func TestReadWithSource_Fallbacks(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantID     string
+		wantSource string
+	}{
+		{
+			name:  "no files",
+			files: map[string]string{},
+		},
+		{
+			name: "dot file takes precedence",
+			files: map[string]string{
+				".hvm":           "v0.153.0/standard",
+				".tool-versions": "hugo 0.152.0\n",
+			},
+			wantID:     "v0.153.0/standard",
+			wantSource: ".hvm",
+		},
+		{
+			name:       "tool-versions standard",
+			files:      map[string]string{".tool-versions": "nodejs 22.0.0\nhugo 0.152.0 # pinned\n"},
+			wantID:     "v0.152.0/standard",
+			wantSource: ".tool-versions",
+		},
+		{
+			name:       "tool-versions edition prefix",
+			files:      map[string]string{".tool-versions": "hugo extended_withdeploy_0.152.0\n"},
+			wantID:     "v0.152.0/extended_withdeploy",
+			wantSource: ".tool-versions",
+		},
+		{
+			name: "tool-versions without hugo falls through",
+			files: map[string]string{
+				".tool-versions": "nodejs 22.0.0\n",
+				"mise.toml":      "[tools]\n\"aqua:gohugoio/hugo/hugo-extended\" = \"0.151.0\"\n",
+			},
+			wantID:     "v0.151.0/extended",
+			wantSource: "mise.toml",
+		},
+		{
+			name:       "mise table",
+			files:      map[string]string{"mise.toml": "[tools]\nhugo = { version = \"v0.150.0\" }\n"},
+			wantID:     "v0.150.0/standard",
+			wantSource: "mise.toml",
+		},
+		{
+			name:       "hugo-version default edition",
+			files:      map[string]string{".hugo-version": "0.149.0\n"},
+			wantID:     "v0.149.0/withdeploy",
+			wantSource: ".hugo-version",
+		},
+		{
+			name:       "hugo-version with edition",
+			files:      map[string]string{".hugo-version": "v0.149.0/extended\n"},
+			wantID:     "v0.149.0/extended",
+			wantSource: ".hugo-version",
+		},
+		{
+			name:       "netlify",
+			files:      map[string]string{"netlify.toml": "[build.environment]\nHUGO_VERSION = \"0.148.0\"\n"},
+			wantID:     "v0.148.0/extended",
+			wantSource: "netlify.toml",
+		},
+		{
+			name:  "netlify without HUGO_VERSION",
+			files: map[string]string{"netlify.toml": "[build]\npublish = \"public\"\n"},
+		},
+		{
+			name:  "tool-versions system",
+			files: map[string]string{".tool-versions": "hugo system\n"},
+		},
+		{
+			name: "tool-versions latest falls through",
+			files: map[string]string{
+				".tool-versions": "hugo latest\n",
+				".hugo-version":  "0.149.0\n",
+			},
+			wantID:     "v0.149.0/withdeploy",
+			wantSource: ".hugo-version",
+		},
+		{
+			name:  "tool-versions ref",
+			files: map[string]string{".tool-versions": "hugo ref:master\n"},
+		},
+		{
+			name:  "mise latest",
+			files: map[string]string{"mise.toml": "[tools]\nhugo = \"latest\"\n"},
+		},
+		{
+			name:  "netlify latest",
+			files: map[string]string{"netlify.toml": "[build.environment]\nHUGO_VERSION = \"latest\"\n"},
+		},
+		{
+			name:  "invalid edition",
+			files: map[string]string{".hugo-version": "v0.149.0/bogus\n"},
+		},
+		{
+			name: "invalid toml falls through",
+			files: map[string]string{
+				"mise.toml":    "[tools\n",
+				"netlify.toml": "[build.environment]\nHUGO_VERSION = \"0.148.0\"\n",
+			},
+			wantID:     "v0.148.0/extended",
+			wantSource: "netlify.toml",
+		},
+		{
+			name:  "file not in fallbacks",
+			files: map[string]string{".mise.toml": "[tools]\nhugo = \"0.147.0\"\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatalf("write: %v", err)
+				}
+			}
+			m := NewManager(filepath.Join(dir, ".hvm"), ".hvm", "hvm", "withdeploy", DefaultFallbacks, "")
+			gotID, gotSource, err := m.ReadWithSource()
+			if err != nil {
+				t.Fatalf("ReadWithSource() unexpected error: %v", err)
+			}
+			if gotID != tt.wantID || gotSource != tt.wantSource {
+				t.Fatalf("ReadWithSource(): want (%q, %q) got (%q, %q)", tt.wantID, tt.wantSource, gotID, gotSource)
+			}
+		})
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	for _, name := range DefaultFallbacks {
+		if !IsFallback(name) {
+			t.Errorf("IsFallback(%q): want true got false", name)
+		}
+	}
+	if IsFallback(".hvm") {
+		t.Error(`IsFallback(".hvm"): want false got true`)
+	}
+	if got := len(Fallbacks()); got != 5 {
+		t.Errorf("len(Fallbacks()): want 5 got %d", got)
+	}
+}
+
+func TestRead_LinkedBuild(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".hvm")
+	if err := os.WriteFile(path, []byte("local/patched-1.0\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
+	got, err := m.Read()
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if want := "local/patched-1.0"; got != want {
+		t.Fatalf("Read(): want %q got %q", want, got)
+	}
+
+	if err := os.WriteFile(path, []byte("local/-bad"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := m.Read(); err == nil || !strings.Contains(err.Error(), "invalid format") {
+		t.Fatalf("Read() error: want to contain %q got %v", "invalid format", err)
+	}
+}
+
+func TestRead_Alias(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".hvm")
+	if err := os.WriteFile(path, []byte("@production\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
+	got, err := m.Read()
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if want := "@production"; got != want {
+		t.Fatalf("Read(): want %q got %q", want, got)
+	}
+
+	if err := os.WriteFile(path, []byte("@"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := m.Read(); err == nil || !strings.Contains(err.Error(), "invalid format") {
+		t.Fatalf("Read() error: want to contain %q got %v", "invalid format", err)
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dotfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jmooring/hvm/repository"
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/mod/semver"
+)
+
+// DefaultFallbacks is the default ordered list of files that Read consults
+// when the current directory does not contain a dot file.
+var DefaultFallbacks = []string{".tool-versions", "mise.toml", ".hugo-version", "netlify.toml"}
+
+// A fallbackParser returns the version and edition pinned by the content of
+// a fallback file, or an empty version if the file does not pin Hugo. An empty
+// edition selects the default edition.
+type fallbackParser func(data []byte) (version, edition string, err error)
+
+// fallbackParsers maps the name of each supported fallback file to its parser.
+var fallbackParsers = map[string]fallbackParser{
+	".hugo-version":  parseHugoVersion,
+	".mise.toml":     parseMise,
+	".tool-versions": parseToolVersions,
+	"mise.toml":      parseMise,
+	"netlify.toml":   parseNetlify,
+}
+
+// Fallbacks returns the names of the supported fallback files in sorted order.
+func Fallbacks() []string {
+	names := make([]string, 0, len(fallbackParsers))
+	for name := range fallbackParsers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// IsFallback reports whether name is a supported fallback file.
+func IsFallback(name string) bool {
+	_, ok := fallbackParsers[name]
+	return ok
+}
+
+// parseHugoVersion parses a .hugo-version file, which contains a version
+// (e.g. 0.160.0), optionally followed by an edition (e.g. v0.160.0/extended)
+// or preceded by one (e.g. extended_0.160.0).
+func parseHugoVersion(data []byte) (string, string, error) {
+	s := strings.TrimSpace(string(data))
+	if version, edition, ok := strings.Cut(s, "/"); ok {
+		return version, edition, nil
+	}
+	version, edition := cutEditionPrefix(s)
+	return version, edition, nil
+}
+
+// parseToolVersions parses an asdf .tool-versions file. The hugo tool selects
+// the standard edition unless the version has an edition prefix
+// (e.g. extended_0.160.0), and the hugo-extended tool selects the extended
+// edition.
+func parseToolVersions(data []byte) (string, string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if version, edition, ok := toolVersion(fields[0], fields[1]); ok {
+			return version, edition, nil
+		}
+	}
+	return "", "", sc.Err()
+}
+
+// parseMise parses the tools section of a mise.toml file. Tools are named as
+// in .tool-versions, optionally with a backend prefix (e.g. "asdf:hugo" or
+// "aqua:gohugoio/hugo/hugo-extended").
+func parseMise(data []byte) (string, string, error) {
+	var v struct {
+		Tools map[string]any `toml:"tools"`
+	}
+	if err := toml.Unmarshal(data, &v); err != nil {
+		return "", "", err
+	}
+
+	names := make([]string, 0, len(v.Tools))
+	for name := range v.Tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		var version string
+		switch t := v.Tools[name].(type) {
+		case string:
+			version = t
+		case []any:
+			if len(t) > 0 {
+				version, _ = t[0].(string)
+			}
+		case map[string]any:
+			version, _ = t["version"].(string)
+		}
+		if i := strings.LastIndexAny(name, ":/"); i >= 0 {
+			name = name[i+1:]
+		}
+		if version, edition, ok := toolVersion(name, version); ok {
+			return version, edition, nil
+		}
+	}
+	return "", "", nil
+}
+
+// parseNetlify parses the HUGO_VERSION environment variable of the build
+// section of a netlify.toml file. Netlify installs the extended edition.
+func parseNetlify(data []byte) (string, string, error) {
+	var v struct {
+		Build struct {
+			Environment map[string]any `toml:"environment"`
+		} `toml:"build"`
+	}
+	if err := toml.Unmarshal(data, &v); err != nil {
+		return "", "", err
+	}
+	version, _ := v.Build.Environment["HUGO_VERSION"].(string)
+	if version == "" {
+		return "", "", nil
+	}
+	return version, "extended", nil
+}
+
+// toolVersion returns the version and edition pinned for tool, and false if
+// tool is not Hugo.
+func toolVersion(tool, version string) (string, string, bool) {
+	switch tool {
+	case "hugo":
+		version, edition := cutEditionPrefix(version)
+		if edition == "" {
+			edition = "standard"
+		}
+		return version, edition, true
+	case "hugo-extended":
+		return version, "extended", true
+	}
+	return "", "", false
+}
+
+// cutEditionPrefix splits a version with an edition prefix
+// (e.g. extended_0.160.0) into its version and edition. The edition is empty
+// if s has no edition prefix.
+func cutEditionPrefix(s string) (version, edition string) {
+	// Try the longest edition names first.
+	editions := slices.Clone(repository.ValidEditions)
+	slices.SortFunc(editions, func(a, b string) int { return len(b) - len(a) })
+	for _, e := range editions {
+		if v, ok := strings.CutPrefix(s, e+"_"); ok {
+			return v, e
+		}
+	}
+	return s, ""
+}
+
+// readFallback returns the build identifier pinned by the content of the
+// fallback file name, or an empty string if the file does not pin Hugo.
+//
+// Fallback files belong to other tools, which accept values that hvm cannot
+// use, such as "hugo system" or "hugo latest" in .tool-versions. A file that
+// pins Hugo to such a value does not pin a version for hvm. A file that cannot
+// be parsed does not pin one either, and a warning is printed to stderr, so
+// that an unrelated error in the file does not prevent hvm from running.
+func (m *Manager) readFallback(name string, data []byte) string {
+	version, edition, err := fallbackParsers[name](data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring the %s file in the current directory: %s\n", name, err)
+		return ""
+	}
+	if version == "" {
+		return ""
+	}
+
+	tag := version
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	if semver.Canonical(tag) != tag {
+		return "" // e.g. "system" or "latest"
+	}
+
+	if edition == "" {
+		edition = m.defaultEdition
+	}
+	if !slices.Contains(repository.ValidEditions, edition) {
+		return ""
+	}
+
+	return tag + "/" + edition
+}
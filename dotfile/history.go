@@ -0,0 +1,166 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dotfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxHistoryEntries is the number of entries retained in the history of each
+// directory.
+const maxHistoryEntries = 100
+
+// An Entry records that a build identifier was written to the dot file of a
+// directory.
+type Entry struct {
+	BuildID string    `json:"buildID"`
+	Time    time.Time `json:"time"`
+}
+
+// A History maps the path of each directory to the entries recorded for its
+// dot file, oldest first.
+type History map[string][]Entry
+
+// ReadHistory reads the history from the file at path. It returns an empty
+// history if the file does not exist.
+func ReadHistory(path string) (History, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return History{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	h := History{}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("invalid history in %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// Write writes the history to the file at path, creating its directory if
+// necessary.
+func (h History) Write(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// History returns the entries recorded for the dot file, oldest first. It
+// returns nil if the manager does not keep a history.
+func (m *Manager) History() ([]Entry, error) {
+	if m.historyFilePath == "" {
+		return nil, nil
+	}
+	h, err := ReadHistory(m.historyFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return h[filepath.Dir(m.filePath)], nil
+}
+
+// Previous returns the most recently recorded build identifier that differs
+// from the content of the dot file, or an empty string if there is none.
+func (m *Manager) Previous() (string, error) {
+	entries, err := m.History()
+	if err != nil {
+		return "", err
+	}
+	current, err := m.content()
+	if err != nil {
+		return "", err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].BuildID != current {
+			return entries[i].BuildID, nil
+		}
+	}
+	return "", nil
+}
+
+// record records that buildID was written to the dot file. If the directory
+// has no history, replaced (the entry for the content buildID replaces) is
+// recorded first unless its BuildID is empty.
+func (m *Manager) record(buildID string, replaced Entry) error {
+	if m.historyFilePath == "" {
+		return nil
+	}
+	h, err := ReadHistory(m.historyFilePath)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(m.filePath)
+	entries := h[dir]
+	if len(entries) == 0 && replaced.BuildID != "" {
+		entries = append(entries, replaced)
+	}
+	if len(entries) > 0 && entries[len(entries)-1].BuildID == buildID {
+		return nil // unchanged
+	}
+
+	entries = append(entries, Entry{BuildID: buildID, Time: time.Now().UTC()})
+	if len(entries) > maxHistoryEntries {
+		entries = entries[len(entries)-maxHistoryEntries:]
+	}
+	h[dir] = entries
+
+	return h.Write(m.historyFilePath)
+}
+
+// currentEntry returns an entry for the current content of the dot file, with
+// the time that the file was last modified. The BuildID of the entry is empty
+// if the dot file does not exist or is empty, or if no history is kept.
+func (m *Manager) currentEntry() (Entry, error) {
+	if m.historyFilePath == "" {
+		return Entry{}, nil
+	}
+	current, err := m.content()
+	if err != nil || current == "" {
+		return Entry{}, err
+	}
+	fi, err := os.Stat(m.filePath)
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{BuildID: current, Time: fi.ModTime().UTC()}, nil
+}
+
+// content returns the trimmed content of the dot file, or an empty string if
+// it does not exist.
+func (m *Manager) content() (string, error) {
+	data, err := os.ReadFile(m.filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dotfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite_History(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".hvm")
+	historyPath := filepath.Join(t.TempDir(), "hvm", "history.json")
+
+	// The existing dot file is recorded before the first write.
+	if err := os.WriteFile(path, []byte("v0.150.0/extended\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, historyPath)
+	for _, buildID := range []string{"v0.151.0/extended", "v0.152.0/standard", "v0.152.0/standard"} {
+		if err := m.Write(buildID); err != nil {
+			t.Fatalf("Write(%q) error: %v", buildID, err)
+		}
+	}
+
+	entries, err := m.History()
+	if err != nil {
+		t.Fatalf("History() error: %v", err)
+	}
+	want := []string{"v0.150.0/extended", "v0.151.0/extended", "v0.152.0/standard"}
+	if len(entries) != len(want) {
+		t.Fatalf("History(): want %d entries got %v", len(want), entries)
+	}
+	for i, e := range entries {
+		if e.BuildID != want[i] {
+			t.Errorf("History()[%d]: want %q got %q", i, want[i], e.BuildID)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("History()[%d]: time is zero", i)
+		}
+	}
+
+	got, err := m.Previous()
+	if err != nil {
+		t.Fatalf("Previous() error: %v", err)
+	}
+	if want := "v0.151.0/extended"; got != want {
+		t.Fatalf("Previous(): want %q got %q", want, got)
+	}
+
+	// Switching back makes the version switched from the previous one.
+	if err := m.Write(got); err != nil {
+		t.Fatalf("Write(%q) error: %v", got, err)
+	}
+	got, err = m.Previous()
+	if err != nil {
+		t.Fatalf("Previous() error: %v", err)
+	}
+	if want := "v0.152.0/standard"; got != want {
+		t.Fatalf("Previous(): want %q got %q", want, got)
+	}
+
+	// The history of each directory is separate.
+	other := NewManager(filepath.Join(t.TempDir(), ".hvm"), ".hvm", "hvm", "standard", nil, historyPath)
+	got, err = other.Previous()
+	if err != nil {
+		t.Fatalf("Previous() error: %v", err)
+	}
+	if got != "" {
+		t.Fatalf("Previous(): want empty string got %q", got)
+	}
+}
+
+func TestWrite_FailureNotRecorded(t *testing.T) {
+	// The dot file cannot be written because its path is a directory.
+	path := filepath.Join(t.TempDir(), ".hvm")
+	if err := os.Mkdir(path, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	historyPath := filepath.Join(t.TempDir(), "hvm", "history.json")
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, historyPath)
+	if err := m.Write("v0.152.0/standard"); err == nil {
+		t.Fatal("Write() expected error")
+	}
+	entries, err := m.History()
+	if err != nil {
+		t.Fatalf("History() error: %v", err)
+	}
+	if entries != nil {
+		t.Fatalf("History(): want nil got %v", entries)
+	}
+}
+
+func TestWrite_NoHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".hvm")
+	m := NewManager(path, ".hvm", "hvm", "standard", nil, "")
+	if err := m.Write("v0.152.0/standard"); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	entries, err := m.History()
+	if err != nil {
+		t.Fatalf("History() error: %v", err)
+	}
+	if entries != nil {
+		t.Fatalf("History(): want nil got %v", entries)
+	}
+}
+
+func TestReadHistory_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("["), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ReadHistory(path); err == nil {
+		t.Fatal("ReadHistory() expected error for invalid file")
+	}
+}
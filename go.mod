@@ -4,10 +4,12 @@ go 1.26.3
 
 require (
 	github.com/google/go-github/v81 v81.0.0
+	github.com/klauspost/compress v1.20.1
 	github.com/pelletier/go-toml/v2 v2.4.3
 	github.com/rogpeppe/go-internal v1.15.0
 	github.com/spf13/cobra v1.10.2
 	github.com/spf13/viper v1.21.0
+	github.com/ulikunitz/xz v0.5.17
 	golang.org/x/mod v0.38.0
 	golang.org/x/oauth2 v0.36.0
 )
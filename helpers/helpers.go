@@ -18,12 +18,16 @@ limitations under the License.
 package helpers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 )
 
@@ -104,6 +108,41 @@ func Exists(path string) (bool, error) {
 	return true, nil
 }
 
+// FileDigest returns the SHA-256 hex digest of the named file.
+func FileDigest(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// FileURLToPath returns the local file system path of the file URL u. On
+// Windows, the leading slash before a drive letter is removed, so that
+// file:///C:/hugo becomes C:\hugo.
+func FileURLToPath(u *url.URL) string {
+	p := u.Path
+	if runtime.GOOS == "windows" && len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return filepath.FromSlash(p)
+}
+
+// PathToFileURL returns the file URL for the absolute path p.
+func PathToFileURL(p string) string {
+	p = filepath.ToSlash(p)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // Windows drive letter
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
 // IsString reports whether i is a string.
 func IsString(i any) bool {
 	_, ok := i.(string)
@@ -18,6 +18,7 @@ package helpers
 
 import (
 	"bytes"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -68,6 +69,28 @@ func TestExists(t *testing.T) {
 	}
 }
 
+// TestFileDigest tests the FileDigest function.
+func TestFileDigest(t *testing.T) {
+	t.Parallel()
+
+	fPath := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(fPath, []byte("hugo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FileDigest(fPath)
+	if err != nil {
+		t.Fatalf("FileDigest() error = %v", err)
+	}
+	if want := "0478721f1106c2a631a90181bac7efc77767a3903eb9220687bff8a14e940fa7"; got != want {
+		t.Errorf("FileDigest() got = %q, want %q", got, want)
+	}
+
+	if _, err := FileDigest(filepath.Join(t.TempDir(), "does-not-exist.txt")); err == nil {
+		t.Error("FileDigest() expected error for missing file")
+	}
+}
+
 // TestCopyFile tests the CopyFile function.
 func TestCopyFile(t *testing.T) {
 	t.Parallel()
@@ -241,3 +264,26 @@ func TestIsString(t *testing.T) {
 		})
 	}
 }
+
+// TestFileURL tests the PathToFileURL and FileURLToPath functions.
+func TestFileURL(t *testing.T) {
+	t.Parallel()
+
+	p, err := filepath.Abs(filepath.Join("testdata", "d1", "f3.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := PathToFileURL(p)
+	if !strings.HasPrefix(s, "file:///") {
+		t.Fatalf("PathToFileURL(%q): want file:/// prefix, got %q", p, s)
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FileURLToPath(u); got != p {
+		t.Fatalf("FileURLToPath(%q): want %q got %q", s, p, got)
+	}
+}
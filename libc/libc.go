@@ -0,0 +1,136 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package libc detects the C standard library of a Linux host, which
+// determines whether dynamically linked Hugo executables can run on it.
+package libc
+
+import (
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// The C standard library families reported by Detect.
+const (
+	Glibc = "glibc"
+	Musl  = "musl"
+)
+
+// An Info describes the C standard library of the host.
+type Info struct {
+	Family      string // Glibc, Musl, or empty if unknown or not Linux
+	Version     string // Version of the library (e.g. 2.36), or empty if unknown; reported, not compared
+	GlibcLoader bool   // Whether a glibc dynamic loader is installed, natively or by a compatibility layer such as gcompat
+}
+
+// String returns a description of the library (e.g. glibc 2.36).
+func (i Info) String() string {
+	if i.Family == "" {
+		return "unknown"
+	}
+	s := i.Family
+	if i.Version != "" {
+		s += " " + i.Version
+	}
+	if i.Family == Musl && i.GlibcLoader {
+		s += " with a glibc compatibility layer"
+	}
+	return s
+}
+
+// RunsGlibc reports whether executables dynamically linked against glibc can
+// run on the host. It returns true unless the host is known to lack a glibc
+// dynamic loader.
+func (i Info) RunsGlibc() bool {
+	return i.Family != Musl || i.GlibcLoader
+}
+
+// Detect returns the C standard library of the host. It returns the zero
+// Info on operating systems other than Linux.
+func Detect() Info {
+	if runtime.GOOS != "linux" {
+		return Info{}
+	}
+	return detect("/", func(name string, args ...string) ([]byte, error) {
+		return exec.Command(name, args...).CombinedOutput()
+	})
+}
+
+// Patterns matching the dynamic loaders of musl and glibc on each
+// architecture, relative to the root directory.
+var (
+	muslLoaders  = []string{"lib/ld-musl-*.so.1"}
+	glibcLoaders = []string{"lib/ld-linux*.so.*", "lib64/ld-linux*.so.*", "lib/ld64.so.*", "lib64/ld64.so.*"}
+)
+
+var (
+	muslVersionRE  = regexp.MustCompile(`(?m)^Version (\d+(?:\.\d+)+)`)
+	glibcVersionRE = regexp.MustCompile(`(?i)(?:glibc|gnu libc)[^\n]*?(\d+\.\d+(?:\.\d+)?)`)
+)
+
+// detect returns the C standard library of the system whose root directory
+// is root, running commands with run. glibc is detected first, because a
+// glibc system may also have musl installed alongside it (e.g. the Debian musl
+// package), so the presence of the musl loader alone does not make the system
+// a musl system.
+func detect(root string, run func(name string, args ...string) ([]byte, error)) Info {
+	var i Info
+	i.GlibcLoader = globAny(root, glibcLoaders) != ""
+
+	if out, err := run("getconf", "GNU_LIBC_VERSION"); err == nil {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(string(out)), "glibc "); ok {
+			i.Family, i.Version = Glibc, v
+			return i
+		}
+	}
+	if out, err := run("ldd", "--version"); err == nil {
+		if m := glibcVersionRE.FindSubmatch(out); m != nil {
+			i.Family, i.Version = Glibc, string(m[1])
+			return i
+		}
+	}
+
+	if loader := globAny(root, muslLoaders); loader != "" {
+		i.Family = Musl
+		// The musl loader prints its version when run without arguments,
+		// exiting with a non-zero status.
+		out, _ := run(loader)
+		if m := muslVersionRE.FindSubmatch(out); m != nil {
+			i.Version = string(m[1])
+		}
+		return i
+	}
+
+	if i.GlibcLoader {
+		i.Family = Glibc
+	}
+	return i
+}
+
+// globAny returns the first file within root that matches one of patterns,
+// or an empty string if none match.
+func globAny(root string, patterns []string) string {
+	for _, p := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(root, p))
+		if len(matches) > 0 {
+			return matches[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	const muslOutput = "musl libc (x86_64)\nVersion 1.2.4\nDynamic Program Loader\nUsage: ld-musl-x86_64.so.1 [options] [--] pathname [args]\n"
+
+	tests := []struct {
+		name   string
+		files  []string
+		output map[string]string // command output, keyed by command name
+		want   Info
+	}{
+		{
+			name:   "glibc",
+			files:  []string{"lib64/ld-linux-x86-64.so.2"},
+			output: map[string]string{"getconf": "glibc 2.36\n"},
+			want:   Info{Family: Glibc, Version: "2.36", GlibcLoader: true},
+		},
+		{
+			name:   "glibcLdd",
+			files:  []string{"lib/ld-linux-aarch64.so.1"},
+			output: map[string]string{"ldd": "ldd (Debian GLIBC 2.31-13+deb11u5) 2.31\nCopyright (C) 2020 Free Software Foundation, Inc.\n"},
+			want:   Info{Family: Glibc, Version: "2.31", GlibcLoader: true},
+		},
+		{
+			name:  "glibcLoaderOnly",
+			files: []string{"lib/ld64.so.1"},
+			want:  Info{Family: Glibc, GlibcLoader: true},
+		},
+		{
+			name:   "glibcWithMusl",
+			files:  []string{"lib/ld-musl-x86_64.so.1", "lib64/ld-linux-x86-64.so.2"},
+			output: map[string]string{"getconf": "glibc 2.39\n", "ld-musl-x86_64.so.1": muslOutput},
+			want:   Info{Family: Glibc, Version: "2.39", GlibcLoader: true},
+		},
+		{
+			name:   "glibcLddWithMusl",
+			files:  []string{"lib/ld-musl-aarch64.so.1", "lib/ld-linux-aarch64.so.1"},
+			output: map[string]string{"ldd": "ldd (Ubuntu GLIBC 2.39-0ubuntu8) 2.39\n", "ld-musl-aarch64.so.1": muslOutput},
+			want:   Info{Family: Glibc, Version: "2.39", GlibcLoader: true},
+		},
+		{
+			name:   "muslLdd",
+			files:  []string{"lib/ld-musl-x86_64.so.1"},
+			output: map[string]string{"ldd": muslOutput, "ld-musl-x86_64.so.1": muslOutput},
+			want:   Info{Family: Musl, Version: "1.2.4"},
+		},
+		{
+			name:   "musl",
+			files:  []string{"lib/ld-musl-x86_64.so.1"},
+			output: map[string]string{"ld-musl-x86_64.so.1": muslOutput},
+			want:   Info{Family: Musl, Version: "1.2.4"},
+		},
+		{
+			name:   "gcompat",
+			files:  []string{"lib/ld-musl-x86_64.so.1", "lib/ld-linux-x86-64.so.2"},
+			output: map[string]string{"ld-musl-x86_64.so.1": muslOutput},
+			want:   Info{Family: Musl, Version: "1.2.4", GlibcLoader: true},
+		},
+		{
+			name: "unknown",
+			want: Info{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, f := range tt.files {
+				name := filepath.Join(root, f)
+				if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(name, nil, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			run := func(name string, args ...string) ([]byte, error) {
+				out, ok := tt.output[filepath.Base(name)]
+				if !ok {
+					return nil, errors.New("not found")
+				}
+				return []byte(out), nil
+			}
+			if got := detect(root, run); got != tt.want {
+				t.Errorf("detect(): want %+v got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInfo(t *testing.T) {
+	tests := []struct {
+		info      Info
+		str       string
+		runsGlibc bool
+	}{
+		{Info{Family: Glibc, Version: "2.36", GlibcLoader: true}, "glibc 2.36", true},
+		{Info{Family: Musl, Version: "1.2.4"}, "musl 1.2.4", false},
+		{Info{Family: Musl, Version: "1.2.4", GlibcLoader: true}, "musl 1.2.4 with a glibc compatibility layer", true},
+		{Info{}, "unknown", true},
+	}
+	for _, tt := range tests {
+		if got := tt.info.String(); got != tt.str {
+			t.Errorf("%+v String(): want %q got %q", tt.info, tt.str, got)
+		}
+		if got := tt.info.RunsGlibc(); got != tt.runsGlibc {
+			t.Errorf("%+v RunsGlibc(): want %t got %t", tt.info, tt.runsGlibc, got)
+		}
+	}
+}
+
+func TestDetect_Host(t *testing.T) {
+	i := Detect()
+	if !slices.Contains([]string{"", Glibc, Musl}, i.Family) {
+		t.Fatalf("Detect(): unexpected family %q", i.Family)
+	}
+}
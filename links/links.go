@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package links manages locally built Hugo executables registered by name.
+//
+// A linked build is identified by "local/<name>" wherever a release is
+// identified by "version/edition", such as in the dot file. The registry maps
+// each name to the path of the executable, which is not copied, so that
+// rebuilding the executable updates the linked build.
+package links
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Namespace is the first element of the build identifier of a linked build.
+const Namespace = "local"
+
+// nameRE matches a valid name of a linked build.
+var nameRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ValidateName reports an error if name is not a valid name of a linked build.
+func ValidateName(name string) error {
+	if !nameRE.MatchString(name) {
+		return fmt.Errorf("invalid name %q: must begin with a letter or digit, followed by up to 63 letters, digits, periods, hyphens, or underscores", name)
+	}
+	return nil
+}
+
+// BuildID returns the build identifier of the linked build name.
+func BuildID(name string) string {
+	return Namespace + "/" + name
+}
+
+// ParseBuildID returns the name of the linked build identified by buildID
+// (e.g. local/patched), or false if buildID does not identify a linked build.
+func ParseBuildID(buildID string) (string, bool) {
+	name, ok := strings.CutPrefix(buildID, Namespace+"/")
+	if !ok || ValidateName(name) != nil {
+		return "", false
+	}
+	return name, true
+}
+
+// A Registry maps the names of linked builds to the paths of their
+// executables.
+type Registry map[string]string
+
+// Read reads the registry from the file at path. It returns an empty registry
+// if the file does not exist.
+func Read(path string) (Registry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Registry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r := Registry{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid registry of linked builds in %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Write writes the registry to the file at path, creating its directory if
+// necessary.
+func (r Registry) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package links
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBuildID(t *testing.T) {
+	tests := []struct {
+		buildID  string
+		wantName string
+		wantOK   bool
+	}{
+		{"local/patched", "patched", true},
+		{"local/v0.154.0-fix_1", "v0.154.0-fix_1", true},
+		{"local/", "", false},
+		{"local/-patched", "", false},
+		{"local/a/b", "", false},
+		{"v0.153.0/extended", "", false},
+		{"patched", "", false},
+	}
+	for _, tt := range tests {
+		name, ok := ParseBuildID(tt.buildID)
+		if name != tt.wantName || ok != tt.wantOK {
+			t.Errorf("ParseBuildID(%q): want (%q, %v) got (%q, %v)", tt.buildID, tt.wantName, tt.wantOK, name, ok)
+		}
+	}
+	if got := BuildID("patched"); got != "local/patched" {
+		t.Errorf("BuildID: want %q got %q", "local/patched", got)
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hvm", "links.json")
+
+	r, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if len(r) != 0 {
+		t.Fatalf("Read(): want empty registry got %v", r)
+	}
+
+	r["patched"] = "/src/hugo/hugo"
+	if err := r.Write(path); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if got["patched"] != "/src/hugo/hugo" || len(got) != 1 {
+		t.Fatalf("round-trip: got %v", got)
+	}
+
+	if err := os.WriteFile(path, []byte("["), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Read(path); err == nil {
+		t.Fatal("Read() expected error for invalid file")
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mirror rewrites release download URLs according to user-defined
+// mirror rules.
+package mirror
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// A Rule rewrites a download URL to point at a mirror. A rule either replaces
+// a leading Prefix of the URL with Replace, or builds a new URL from Template.
+//
+// Template is a text/template that may reference {{.Tag}}, {{.Edition}},
+// {{.OS}}, {{.Arch}}, and {{.File}}, where File is the last element of the
+// original URL path (e.g. hugo_extended_0.153.0_linux-amd64.tar.gz).
+type Rule struct {
+	Prefix   string `mapstructure:"prefix"   toml:"prefix"`   // URL prefix to replace
+	Replace  string `mapstructure:"replace"  toml:"replace"`  // Replacement for Prefix
+	Template string `mapstructure:"template" toml:"template"` // URL template; mutually exclusive with Prefix
+}
+
+// Data contains the values available to a Rule template.
+type Data struct {
+	Tag     string // Release tag (e.g. v0.153.0)
+	Edition string // Edition (e.g. extended)
+	OS      string // Operating system (e.g. linux)
+	Arch    string // Architecture (e.g. amd64)
+	File    string // Last element of the original URL path
+}
+
+// Validate reports whether the rule is well formed.
+func (r Rule) Validate() error {
+	switch {
+	case r.Prefix == "" && r.Template == "":
+		return fmt.Errorf("either prefix or template must be set")
+	case r.Prefix != "" && r.Template != "":
+		return fmt.Errorf("prefix and template are mutually exclusive")
+	case r.Prefix != "" && r.Replace == "":
+		return fmt.Errorf("replace must be set when prefix is set")
+	case r.Template != "":
+		if _, err := template.New("").Option("missingkey=error").Parse(r.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	return nil
+}
+
+// Rewrite applies the rule to u. It returns false if the rule does not apply,
+// which happens when a prefix rule's Prefix is not a prefix of u.
+func (r Rule) Rewrite(u string, d Data) (string, bool, error) {
+	if r.Prefix != "" {
+		if !strings.HasPrefix(u, r.Prefix) {
+			return "", false, nil
+		}
+		return r.Replace + strings.TrimPrefix(u, r.Prefix), true, nil
+	}
+
+	tmpl, err := template.New("").Option("missingkey=error").Parse(r.Template)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid template: %w", err)
+	}
+	if d.File == "" {
+		d.File = fileName(u)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return "", false, err
+	}
+	return buf.String(), true, nil
+}
+
+// URLs returns the URLs from which to download u, in the order they should be
+// tried: the rewrite of each applicable rule, in rule order, followed by u
+// itself. Duplicate URLs are removed.
+func URLs(rules []Rule, u string, d Data) ([]string, error) {
+	var urls []string
+	for i, r := range rules {
+		rewritten, ok, err := r.Rewrite(u, d)
+		if err != nil {
+			return nil, fmt.Errorf("mirror rule %d: %w", i, err)
+		}
+		if ok {
+			urls = appendUnique(urls, rewritten)
+		}
+	}
+	return appendUnique(urls, u), nil
+}
+
+// appendUnique appends s to list unless list already contains s.
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+// fileName returns the last element of the path of u.
+func fileName(u string) string {
+	if pu, err := url.Parse(u); err == nil {
+		return path.Base(pu.Path)
+	}
+	return path.Base(u)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"slices"
+	"testing"
+)
+
+const origin = "https://github.com/gohugoio/hugo/releases/download/v0.153.0/hugo_extended_0.153.0_linux-amd64.tar.gz"
+
+var data = Data{Tag: "v0.153.0", Edition: "extended", OS: "linux", Arch: "amd64"}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{"prefix", Rule{Prefix: "https://github.com/", Replace: "https://mirror.example.org/"}, false},
+		{"template", Rule{Template: "https://mirror.example.org/{{.Tag}}/{{.File}}"}, false},
+		{"empty", Rule{}, true},
+		{"both", Rule{Prefix: "https://github.com/", Replace: "x", Template: "y"}, true},
+		{"prefixWithoutReplace", Rule{Prefix: "https://github.com/"}, true},
+		{"badTemplate", Rule{Template: "https://mirror.example.org/{{.Tag"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   Rule
+		want   string
+		wantOK bool
+	}{
+		{
+			"prefix",
+			Rule{Prefix: "https://github.com/", Replace: "https://artifactory.example.com/artifactory/github/"},
+			"https://artifactory.example.com/artifactory/github/gohugoio/hugo/releases/download/v0.153.0/hugo_extended_0.153.0_linux-amd64.tar.gz",
+			true,
+		},
+		{
+			"prefixNoMatch",
+			Rule{Prefix: "https://example.org/", Replace: "https://mirror.example.org/"},
+			"",
+			false,
+		},
+		{
+			"template",
+			Rule{Template: "https://mirror.example.org/hugo/{{.Tag}}/{{.Edition}}/{{.OS}}-{{.Arch}}/{{.File}}"},
+			"https://mirror.example.org/hugo/v0.153.0/extended/linux-amd64/hugo_extended_0.153.0_linux-amd64.tar.gz",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := tt.rule.Rewrite(origin, data)
+			if err != nil {
+				t.Fatalf("Rewrite() error: %v", err)
+			}
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Rewrite(): want (%q, %v) got (%q, %v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestRewrite_UnknownField(t *testing.T) {
+	r := Rule{Template: "https://mirror.example.org/{{.Version}}/{{.File}}"}
+	if _, _, err := r.Rewrite(origin, data); err == nil {
+		t.Fatal("Rewrite() expected error for unknown template field")
+	}
+}
+
+func TestURLs(t *testing.T) {
+	rules := []Rule{
+		{Prefix: "https://example.org/", Replace: "https://unused.example.org/"},
+		{Prefix: "https://github.com/", Replace: "https://a.example.org/"},
+		{Template: "https://b.example.org/{{.File}}"},
+		{Template: "https://b.example.org/{{.File}}"},
+	}
+	got, err := URLs(rules, origin, data)
+	if err != nil {
+		t.Fatalf("URLs() error: %v", err)
+	}
+	want := []string{
+		"https://a.example.org/gohugoio/hugo/releases/download/v0.153.0/hugo_extended_0.153.0_linux-amd64.tar.gz",
+		"https://b.example.org/hugo_extended_0.153.0_linux-amd64.tar.gz",
+		origin,
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("URLs():\nwant %q\ngot  %q", want, got)
+	}
+}
+
+func TestURLs_NoRules(t *testing.T) {
+	got, err := URLs(nil, origin, data)
+	if err != nil {
+		t.Fatalf("URLs() error: %v", err)
+	}
+	if !slices.Equal(got, []string{origin}) {
+		t.Fatalf("URLs(): want only the origin, got %q", got)
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pins finds and updates the Hugo version and edition pinned by
+// third-party configuration files, such as netlify.toml, GitHub Actions
+// workflows, Dockerfiles, and package.json.
+package pins
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// A source is a kind of configuration file that pins a Hugo version.
+type source struct {
+	patterns []string                                  // glob patterns relative to the root directory
+	update   func(lines []string, tag, edition string) // rewrites the pinned lines in place
+}
+
+// sources lists the known locations of Hugo version pins.
+var sources = []source{
+	{[]string{"netlify.toml"}, updateNetlify},
+	{[]string{".github/workflows/*.yml", ".github/workflows/*.yaml"}, updateWorkflow},
+	{[]string{"Dockerfile", "Dockerfile.*", "*.Dockerfile", "*.dockerfile"}, updateDockerfile},
+	{[]string{"package.json"}, updatePackageJSON},
+}
+
+// A Change is a line of a configuration file that pins a different version or
+// edition.
+type Change struct {
+	Line int    // 1-based line number
+	Old  string // current content of the line
+	New  string // updated content of the line
+}
+
+// A File is a configuration file that pins a different version or edition.
+type File struct {
+	Path    string      // path relative to the root directory, using forward slashes
+	Mode    fs.FileMode // permission bits of the file
+	Content []byte      // updated content of the file
+	Changes []Change    // lines that differ, in order
+}
+
+// Check returns the configuration files in root that pin a different version
+// or edition than tag (e.g. v0.160.0) and edition (e.g. extended). Files that
+// pin only a version are compared only by version.
+func Check(root, tag, edition string) ([]File, error) {
+	var files []File
+	for _, s := range sources {
+		var paths []string
+		for _, pattern := range s.patterns {
+			matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, matches...)
+		}
+		slices.Sort(paths)
+		paths = slices.Compact(paths)
+
+		for _, path := range paths {
+			f, err := check(root, path, s, tag, edition)
+			if err != nil {
+				return nil, err
+			}
+			if f != nil {
+				files = append(files, *f)
+			}
+		}
+	}
+	return files, nil
+}
+
+// check applies source s to the file at path, returning nil if the file does
+// not pin a different version or edition.
+func check(root, path string, s source, tag, edition string) (*File, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	old := strings.Split(string(data), "\n")
+	lines := slices.Clone(old)
+	s.update(lines, tag, edition)
+
+	var changes []Change
+	for i := range lines {
+		if lines[i] != old[i] {
+			changes = append(changes, Change{Line: i + 1, Old: old[i], New: lines[i]})
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		Path:    filepath.ToSlash(rel),
+		Mode:    fi.Mode().Perm(),
+		Content: []byte(strings.Join(lines, "\n")),
+		Changes: changes,
+	}, nil
+}
+
+// Diff returns the changes to f in unified diff format, without context lines.
+func (f File) Diff() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", f.Path, f.Path)
+	for _, c := range f.Changes {
+		fmt.Fprintf(&b, "@@ -%d +%d @@\n-%s\n+%s\n", c.Line, c.Line,
+			strings.TrimSuffix(c.Old, "\r"), strings.TrimSuffix(c.New, "\r"))
+	}
+	return b.String()
+}
+
+var (
+	netlifyRE       = regexp.MustCompile(`^\s*HUGO_VERSION\s*=\s*["']([^"']*)["']`)
+	dockerfileRE    = regexp.MustCompile(`(?i)^\s*ARG\s+HUGO_VERSION=["']?([^"'\s]+)`)
+	packageJSONRE   = regexp.MustCompile(`"hugo-extended"\s*:\s*"(?:[~^=]|[<>]=?)?\s*(v?[0-9][^"\s]*)"`)
+	hugoVersionRE   = regexp.MustCompile(`^\s*hugo-version:\s*["']?([^"'\s#]+)`)
+	extendedRE      = regexp.MustCompile(`^\s*extended:\s*["']?(true|false)\b`)
+	withdeployRE    = regexp.MustCompile(`^\s*withdeploy:\s*["']?(true|false)\b`)
+	actionsHugoRE   = regexp.MustCompile(`^(\s*)(?:-\s+)?uses:\s*["']?peaceiris/actions-hugo\b`)
+	sequenceEntryRE = regexp.MustCompile(`^(\s*)-\s`)
+)
+
+// updateNetlify updates the HUGO_VERSION environment variables of a
+// netlify.toml file. Netlify installs the extended edition.
+func updateNetlify(lines []string, tag, edition string) {
+	for i := range lines {
+		lines[i] = replaceVersion(lines[i], netlifyRE, tag)
+	}
+}
+
+// updateDockerfile updates the default value of the HUGO_VERSION build
+// argument of a Dockerfile.
+func updateDockerfile(lines []string, tag, edition string) {
+	for i := range lines {
+		lines[i] = replaceVersion(lines[i], dockerfileRE, tag)
+	}
+}
+
+// updatePackageJSON updates the version of the hugo-extended dependency of a
+// package.json file, preserving its range operator.
+func updatePackageJSON(lines []string, tag, edition string) {
+	for i := range lines {
+		lines[i] = replaceVersion(lines[i], packageJSONRE, tag)
+	}
+}
+
+// updateWorkflow updates the hugo-version, extended, and withdeploy inputs of
+// each peaceiris/actions-hugo step of a GitHub Actions workflow.
+func updateWorkflow(lines []string, tag, edition string) {
+	extended := fmt.Sprint(strings.Contains(edition, "extended"))
+	withdeploy := fmt.Sprint(strings.Contains(edition, "withdeploy"))
+
+	for i, line := range lines {
+		m := actionsHugoRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		start, end := stepBounds(lines, i, len(m[1]))
+		for j := start; j < end; j++ {
+			lines[j] = replaceVersion(lines[j], hugoVersionRE, tag)
+			lines[j] = replace(lines[j], extendedRE, extended)
+			lines[j] = replace(lines[j], withdeployRE, withdeploy)
+		}
+	}
+}
+
+// stepBounds returns the range of lines of the workflow step containing the
+// uses key at line i, indented by indent columns.
+func stepBounds(lines []string, i, indent int) (start, end int) {
+	start = i
+	for j := i; j >= 0; j-- {
+		if m := sequenceEntryRE.FindStringSubmatch(lines[j]); m != nil && len(m[1]) <= indent {
+			start = j
+			indent = len(m[1])
+			break
+		}
+	}
+	for end = start + 1; end < len(lines); end++ {
+		trimmed := strings.TrimSpace(lines[end])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") && leadingSpace(lines[end]) <= indent {
+			break
+		}
+	}
+	return start, end
+}
+
+// leadingSpace returns the number of leading spaces and tabs in s.
+func leadingSpace(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " \t"))
+}
+
+// replaceVersion replaces the first submatch of re in line with the version of
+// tag, with or without the leading "v" to match the current value.
+func replaceVersion(line string, re *regexp.Regexp, tag string) string {
+	m := re.FindStringSubmatchIndex(line)
+	if m == nil {
+		return line
+	}
+	version := strings.TrimPrefix(tag, "v")
+	if strings.HasPrefix(line[m[2]:m[3]], "v") {
+		version = tag
+	}
+	return line[:m[2]] + version + line[m[3]:]
+}
+
+// replace replaces the first submatch of re in line with value.
+func replace(line string, re *regexp.Regexp, value string) string {
+	m := re.FindStringSubmatchIndex(line)
+	if m == nil {
+		return line
+	}
+	return line[:m[2]] + value + line[m[3]:]
+}
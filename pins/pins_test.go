@@ -0,0 +1,142 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pins
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	files := map[string]struct {
+		content string
+		want    string // updated content, or empty if unchanged
+	}{
+		"netlify.toml": {
+			content: "[build.environment]\n  HUGO_VERSION = \"0.152.0\"\n\n[context.deploy-preview.environment]\n  HUGO_VERSION = 'v0.150.0'\n",
+			want:    "[build.environment]\n  HUGO_VERSION = \"0.153.0\"\n\n[context.deploy-preview.environment]\n  HUGO_VERSION = 'v0.153.0'\n",
+		},
+		"Dockerfile": {
+			content: "FROM golang:1.26\r\nARG HUGO_VERSION=0.152.0\r\nRUN echo $HUGO_VERSION\r\n",
+			want:    "FROM golang:1.26\r\nARG HUGO_VERSION=0.153.0\r\nRUN echo $HUGO_VERSION\r\n",
+		},
+		"site.Dockerfile": {
+			content: "arg HUGO_VERSION=\"0.153.0\"\n",
+		},
+		"package.json": {
+			content: "{\n  \"devDependencies\": {\n    \"hugo-extended\": \"^0.152.0\"\n  }\n}\n",
+			want:    "{\n  \"devDependencies\": {\n    \"hugo-extended\": \"^0.153.0\"\n  }\n}\n",
+		},
+		".github/workflows/hugo.yml": {
+			content: `jobs:
+  build:
+    steps:
+      - uses: actions/setup-node@v4
+        with:
+          extended: false
+      - name: Setup Hugo
+        uses: peaceiris/actions-hugo@v3
+        with:
+          hugo-version: '0.152.0' # pinned
+          extended: false
+      - run: hugo
+        env:
+          extended: false
+`,
+			want: `jobs:
+  build:
+    steps:
+      - uses: actions/setup-node@v4
+        with:
+          extended: false
+      - name: Setup Hugo
+        uses: peaceiris/actions-hugo@v3
+        with:
+          hugo-version: '0.153.0' # pinned
+          extended: true
+      - run: hugo
+        env:
+          extended: false
+`,
+		},
+		".github/workflows/other.yaml": {
+			content: "jobs:\n  test:\n    steps:\n      - with:\n          hugo-version: 0.152.0\n",
+		},
+	}
+
+	root := t.TempDir()
+	for name, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Check(root, "v0.153.0", "extended")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	changed := map[string]File{}
+	for _, f := range got {
+		changed[f.Path] = f
+	}
+	for name, f := range files {
+		c, ok := changed[name]
+		if f.want == "" {
+			if ok {
+				t.Errorf("%s: unexpected changes:\n%s", name, c.Diff())
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: no changes, want changes", name)
+			continue
+		}
+		if string(c.Content) != f.want {
+			t.Errorf("%s: got content\n%s\nwant\n%s", name, c.Content, f.want)
+		}
+		if c.Mode != 0o644 && os.PathSeparator == '/' {
+			t.Errorf("%s: got mode %v, want 0644", name, c.Mode)
+		}
+	}
+}
+
+func TestFileDiff(t *testing.T) {
+	f := File{
+		Path: "netlify.toml",
+		Changes: []Change{
+			{Line: 2, Old: `  HUGO_VERSION = "0.152.0"` + "\r", New: `  HUGO_VERSION = "0.153.0"` + "\r"},
+		},
+	}
+	want := strings.Join([]string{
+		"--- a/netlify.toml",
+		"+++ b/netlify.toml",
+		"@@ -2 +2 @@",
+		`-  HUGO_VERSION = "0.152.0"`,
+		`+  HUGO_VERSION = "0.153.0"`,
+		"",
+	}, "\n")
+	if got := f.Diff(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// A BuildInfo describes a Hugo executable as reported by its version
+// command.
+type BuildInfo struct {
+	Tag      string   // Release tag (e.g. v0.153.0)
+	Edition  string   // Edition (e.g. extended_withdeploy)
+	Platform Platform // Platform for which the executable was built
+}
+
+// buildInfoRE matches the version, edition, and platform in the output of
+// the version command of current and legacy Hugo releases, for example:
+//
+//	hugo v0.153.0-8e6c5f2b+extended+withdeploy linux/amd64 BuildDate=...
+//	Hugo Static Site Generator v0.80.0-792EF0F4/extended linux/amd64 BuildDate: ...
+//	Hugo Static Site Generator v0.20 linux/amd64 BuildDate: ...
+var buildInfoRE = regexp.MustCompile(`\bv(\d+\.\d+(?:\.\d+)?)(?:-[0-9A-Za-z]+)*((?:[+/][a-z]+)*)\s+([a-z0-9]+)/([a-z0-9]+)`)
+
+// ParseBuildInfo parses the output of the version command of a Hugo
+// executable. The edition is derived from the "extended" and "withdeploy"
+// build tags reported after the version number.
+func ParseBuildInfo(s string) (BuildInfo, error) {
+	m := buildInfoRE.FindStringSubmatch(s)
+	if m == nil {
+		return BuildInfo{}, fmt.Errorf("unable to parse version output %q", firstLine(s))
+	}
+
+	var extended, withdeploy bool
+	for t := range strings.FieldsFuncSeq(m[2], func(r rune) bool { return r == '+' || r == '/' }) {
+		switch t {
+		case "extended":
+			extended = true
+		case "withdeploy":
+			withdeploy = true
+		}
+	}
+
+	edition := "standard"
+	switch {
+	case extended && withdeploy:
+		edition = "extended_withdeploy"
+	case extended:
+		edition = "extended"
+	case withdeploy:
+		edition = "withdeploy"
+	}
+
+	return BuildInfo{
+		Tag:      "v" + m[1],
+		Edition:  edition,
+		Platform: Platform{OS: m[3], Arch: m[4]},
+	}, nil
+}
+
+// firstLine returns the first line of s, without surrounding white space.
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import "testing"
+
+func TestParseBuildInfo(t *testing.T) {
+	tests := []struct {
+		output string
+		want   BuildInfo
+	}{
+		{
+			"hugo v0.153.0-8e6c5f2bd1f3e8a1c6b5b2b8e3f9a2b1c4d5e6f7+extended+withdeploy linux/amd64 BuildDate=2025-12-01T10:00:00Z VendorInfo=gohugoio\n",
+			BuildInfo{"v0.153.0", "extended_withdeploy", Platform{"linux", "amd64"}},
+		},
+		{
+			"hugo v0.153.0-8e6c5f2b+withdeploy darwin/arm64 BuildDate=2025-12-01T10:00:00Z",
+			BuildInfo{"v0.153.0", "withdeploy", Platform{"darwin", "arm64"}},
+		},
+		{
+			"hugo v0.100.0-27b077544d8efeb85867cb4cfb941747d104f765+extended windows/amd64 BuildDate=2022-05-31T08:37:12Z VendorInfo=gohugoio",
+			BuildInfo{"v0.100.0", "extended", Platform{"windows", "amd64"}},
+		},
+		{
+			"hugo v0.152.0 freebsd/amd64 BuildDate=unknown",
+			BuildInfo{"v0.152.0", "standard", Platform{"freebsd", "amd64"}},
+		},
+		{
+			"Hugo Static Site Generator v0.80.0-792EF0F4/extended linux/arm64 BuildDate: 2020-12-31T13:37:57Z",
+			BuildInfo{"v0.80.0", "extended", Platform{"linux", "arm64"}},
+		},
+		{
+			"Hugo Static Site Generator v0.20 linux/386 BuildDate: 2017-04-10T13:16:39Z",
+			BuildInfo{"v0.20", "standard", Platform{"linux", "386"}},
+		},
+		{
+			"hugo v0.154.0-DEV-0123abcd+extended linux/amd64 BuildDate=unknown",
+			BuildInfo{"v0.154.0", "extended", Platform{"linux", "amd64"}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseBuildInfo(tt.output)
+		if err != nil {
+			t.Errorf("ParseBuildInfo(%q) error: %v", tt.output, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBuildInfo(%q): want %+v got %+v", tt.output, tt.want, got)
+		}
+	}
+}
+
+func TestParseBuildInfo_Invalid(t *testing.T) {
+	for _, s := range []string{"", "fake hugo v0.153.0/extended\n", "Segmentation fault\n"} {
+		if _, err := ParseBuildInfo(s); err == nil {
+			t.Errorf("ParseBuildInfo(%q) expected error", s)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"path/filepath"
+
+	"github.com/jmooring/hvm/helpers"
+)
+
+// NewDirRepository creates a new Repository instance whose tags and release
+// assets are read from a local directory of release archives and checksums
+// files, such as one copied onto an air-gapped machine. Archive file names
+// are matched with the same rules used for GitHub release assets, and the
+// archives are downloaded from file URLs.
+func NewDirRepository(dir string) (*Repository, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	urlFor := func(name string) string {
+		return helpers.PathToFileURL(filepath.Join(dir, name))
+	}
+
+	ix, err := indexFromDir(dir, urlFor, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Repository{index: ix}
+
+	err = r.FetchTags()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jmooring/hvm/helpers"
+)
+
+func TestNewDirRepository(t *testing.T) {
+	dir := t.TempDir()
+
+	p := Platform{OS: "linux", Arch: "amd64"}
+	files := map[string]string{
+		"hugo_0.153.0_linux-amd64.tar.gz":          "a",
+		"hugo_extended_0.153.0_linux-amd64.tar.gz": "b",
+		"hugo_0.152.0_linux-amd64.tar.gz":          "c",
+		"hugo_0.153.0_checksums.txt":               "1111  hugo_0.153.0_linux-amd64.tar.gz\n2222  hugo_extended_0.153.0_linux-amd64.tar.gz\n",
+		"notes.txt":                                "d",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewDirRepository(dir)
+	if err != nil {
+		t.Fatalf("NewDirRepository error: %v", err)
+	}
+	if want := []string{"v0.153.0", "v0.152.0"}; !slices.Equal(r.tags, want) {
+		t.Fatalf("tags: want %v got %v", want, r.tags)
+	}
+
+	a := &Asset{Tag: "v0.153.0"}
+	editions, err := r.fetchEditionsFromIndex(a, p)
+	if err != nil {
+		t.Fatalf("fetchEditionsFromIndex error: %v", err)
+	}
+	if len(editions) != 2 {
+		t.Fatalf("editions: want 2 got %v", editions)
+	}
+
+	u, err := url.Parse(editions["extended"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "file" {
+		t.Fatalf("extended URL: want file scheme got %q", editions["extended"])
+	}
+	if want := filepath.Join(dir, "hugo_extended_0.153.0_linux-amd64.tar.gz"); helpers.FileURLToPath(u) != want {
+		t.Fatalf("extended path: want %q got %q", want, helpers.FileURLToPath(u))
+	}
+
+	a.Edition = "extended"
+	if err := a.SetURLFromEditions(editions); err != nil {
+		t.Fatalf("SetURLFromEditions error: %v", err)
+	}
+	if a.Checksum != "2222" {
+		t.Fatalf("Checksum: want 2222 got %q", a.Checksum)
+	}
+
+	// Archives without a checksums file are listed without a digest.
+	a = &Asset{Tag: "v0.152.0", Edition: "standard"}
+	editions, err = r.fetchEditionsFromIndex(a, p)
+	if err != nil {
+		t.Fatalf("fetchEditionsFromIndex error: %v", err)
+	}
+	if err := a.SetURLFromEditions(editions); err != nil {
+		t.Fatalf("SetURLFromEditions error: %v", err)
+	}
+	if a.Checksum != "" {
+		t.Fatalf("Checksum: want empty got %q", a.Checksum)
+	}
+}
+
+func TestNewDirRepository_Missing(t *testing.T) {
+	if _, err := NewDirRepository(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("NewDirRepository: expected error for missing directory")
+	}
+}
@@ -0,0 +1,465 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v81/github"
+	gh "github.com/jmooring/hvm/github"
+	"github.com/jmooring/hvm/helpers"
+	"golang.org/x/mod/semver"
+)
+
+// IndexSchemaVersion is the current release index schema version.
+const IndexSchemaVersion = 1
+
+// An Index is a release index: a JSON document that lists the release assets
+// available for each tag, edition, and platform, with their SHA-256 digests.
+// An index served over plain HTTP may be used as the release source instead
+// of the GitHub API.
+type Index struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Releases      []IndexRelease `json:"releases"` // newest first
+}
+
+// An IndexRelease lists the release assets for a single tag.
+type IndexRelease struct {
+	Tag    string       `json:"tag"`
+	Assets []IndexAsset `json:"assets"`
+}
+
+// An IndexAsset describes a release asset for one edition and platform.
+type IndexAsset struct {
+	Edition string `json:"edition"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	URL     string `json:"url"`              // Absolute, or relative to the index URL
+	SHA256  string `json:"sha256,omitempty"` // SHA-256 hex digest of the archive
+}
+
+// NewIndexRepository creates a new Repository instance whose tags and release
+// assets are read from the release index at indexURL instead of the GitHub API.
+func NewIndexRepository(indexURL string, client *http.Client) (*Repository, error) {
+	ix, err := LoadIndex(indexURL, client)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Repository{index: ix}
+
+	err = r.FetchTags()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// LoadIndex downloads and validates the release index at indexURL. Relative
+// asset URLs are resolved against indexURL.
+func LoadIndex(indexURL string, client *http.Client) (*Index, error) {
+	base, err := url.Parse(indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release index URL: %w", err)
+	}
+
+	resp, err := client.Get(indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("downloading release index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading release index: bad status: %s", resp.Status)
+	}
+
+	ix, err := ReadIndex(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range ix.Releases {
+		for j := range ix.Releases[i].Assets {
+			a := &ix.Releases[i].Assets[j]
+			ref, err := url.Parse(a.URL)
+			if err != nil {
+				return nil, fmt.Errorf("release index: %s: invalid URL %q: %w", ix.Releases[i].Tag, a.URL, err)
+			}
+			a.URL = base.ResolveReference(ref).String()
+		}
+	}
+
+	return ix, nil
+}
+
+// ReadIndex decodes and validates a release index from r.
+func ReadIndex(r io.Reader) (*Index, error) {
+	var ix Index
+	if err := json.NewDecoder(r).Decode(&ix); err != nil {
+		return nil, fmt.Errorf("release index: %w", err)
+	}
+	if ix.SchemaVersion != IndexSchemaVersion {
+		return nil, fmt.Errorf("release index: unsupported schema version %d", ix.SchemaVersion)
+	}
+	for _, rel := range ix.Releases {
+		if !semver.IsValid(rel.Tag) {
+			return nil, fmt.Errorf("release index: invalid tag %q", rel.Tag)
+		}
+		for _, a := range rel.Assets {
+			if !slices.Contains(ValidEditions, a.Edition) {
+				return nil, fmt.Errorf("release index: %s: invalid edition %q", rel.Tag, a.Edition)
+			}
+			if a.URL == "" {
+				return nil, fmt.Errorf("release index: %s: missing URL for %s %s/%s", rel.Tag, a.Edition, a.OS, a.Arch)
+			}
+		}
+	}
+	return &ix, nil
+}
+
+// Write writes the release index to w as indented JSON.
+func (ix *Index) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ix)
+}
+
+// Tags returns the tags in the release index, newest first.
+func (ix *Index) Tags() []string {
+	tags := make([]string, 0, len(ix.Releases))
+	for _, rel := range ix.Releases {
+		tags = append(tags, rel.Tag)
+	}
+	semver.Sort(tags)
+	slices.Reverse(tags)
+	return tags
+}
+
+// sort sorts the releases newest first.
+func (ix *Index) sort() {
+	slices.SortFunc(ix.Releases, func(a, b IndexRelease) int {
+		return semver.Compare(b.Tag, a.Tag)
+	})
+}
+
+// GenerateIndex builds a release index from the GitHub releases of the
+// owner/name repository, downloading each release's checksums files with
+// httpClient. limit is the maximum number of releases to include, newest
+// first; a negative value includes all releases.
+func GenerateIndex(client *github.Client, owner, name string, httpClient *http.Client, limit int) (*Index, error) {
+	ix := &Index{SchemaVersion: IndexSchemaVersion}
+
+	opts := &github.ListOptions{PerPage: 100}
+	var releases []*github.RepositoryRelease
+	for {
+		page, resp, err := client.Repositories.ListReleases(context.Background(), owner, name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s", gh.ErrReason(err))
+		}
+		for _, rel := range page {
+			if rel.GetDraft() || semver.Compare(rel.GetTagName(), firstSemverTag) < 0 {
+				continue
+			}
+			releases = append(releases, rel)
+		}
+		if resp.NextPage == 0 || (limit >= 0 && len(releases) >= limit) {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	slices.SortFunc(releases, func(a, b *github.RepositoryRelease) int {
+		return semver.Compare(b.GetTagName(), a.GetTagName())
+	})
+	if limit >= 0 && len(releases) > limit {
+		releases = releases[:limit]
+	}
+
+	for _, rel := range releases {
+		tag := rel.GetTagName()
+
+		checksums := map[string]string{}
+		for _, asset := range rel.Assets {
+			if !strings.HasSuffix(asset.GetName(), "_checksums.txt") {
+				continue
+			}
+			m, err := FetchChecksums(httpClient, asset.GetBrowserDownloadURL())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", tag, err)
+			}
+			maps.Copy(checksums, m)
+		}
+
+		ir := IndexRelease{Tag: tag}
+		for _, asset := range rel.Assets {
+			ir.Assets = append(ir.Assets, indexAssets(tag, asset.GetBrowserDownloadURL(), checksums[asset.GetName()])...)
+		}
+		if len(ir.Assets) > 0 {
+			ix.Releases = append(ix.Releases, ir)
+		}
+	}
+
+	if len(ix.Releases) == 0 {
+		return nil, fmt.Errorf("no releases found")
+	}
+
+	return ix, nil
+}
+
+// GenerateIndexFromDir builds a release index from the release archives in
+// dir. The digest of each archive is read from the checksums files in dir,
+// or computed if the archive is not listed. Asset URLs are the archive file
+// names resolved against baseURL, or left relative if baseURL is empty, in
+// which case the index must be served from the same location as the archives.
+func GenerateIndexFromDir(dir, baseURL string) (*Index, error) {
+	var base *url.URL
+	if baseURL != "" {
+		var err error
+		base, err = url.Parse(strings.TrimSuffix(baseURL, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL: %w", err)
+		}
+	}
+
+	urlFor := func(name string) string {
+		u := &url.URL{Path: name}
+		if base != nil {
+			return base.ResolveReference(u).String()
+		}
+		return u.String()
+	}
+
+	return indexFromDir(dir, urlFor, true)
+}
+
+// HasArchives reports whether dir contains any release archives from which
+// GenerateIndexFromDir would build an index. The archives are not read.
+func HasArchives(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if tag, ok := tagFromAssetName(e.Name()); ok && len(indexAssets(tag, e.Name(), "")) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// indexFromDir builds a release index from the release archives in dir. The
+// urlFor function returns the asset URL for an archive file name. The digest
+// of each archive is read from the checksums files in dir; if the archive is
+// not listed, its digest is computed when compute is true, and left empty
+// otherwise.
+func indexFromDir(dir string, urlFor func(name string) string, compute bool) (*Index, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums, err := readDirChecksums(dir, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := map[string]*IndexRelease{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		tag, ok := tagFromAssetName(e.Name())
+		if !ok {
+			continue
+		}
+
+		u := urlFor(e.Name())
+
+		// Skip files that do not match any platform before hashing them.
+		if len(indexAssets(tag, u, "")) == 0 {
+			continue
+		}
+		digest, ok := checksums[e.Name()]
+		if !ok && compute {
+			digest, err = helpers.FileDigest(filepath.Join(dir, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		rel, ok := releases[tag]
+		if !ok {
+			rel = &IndexRelease{Tag: tag}
+			releases[tag] = rel
+		}
+		rel.Assets = append(rel.Assets, indexAssets(tag, u, digest)...)
+	}
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no release archives found in %s", dir)
+	}
+
+	ix := &Index{SchemaVersion: IndexSchemaVersion}
+	for _, rel := range releases {
+		ix.Releases = append(ix.Releases, *rel)
+	}
+	ix.sort()
+
+	return ix, nil
+}
+
+// readDirChecksums reads every checksums file (*_checksums.txt) among the
+// directory entries of dir and returns the digests keyed by file name.
+func readDirChecksums(dir string, entries []os.DirEntry) (map[string]string, error) {
+	checksums := map[string]string{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), "_checksums.txt") {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m, err := ParseChecksums(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		maps.Copy(checksums, m)
+	}
+	return checksums, nil
+}
+
+// indexAssets returns an IndexAsset for each platform on which the release
+// asset at assetURL is used.
+func indexAssets(tag, assetURL, digest string) []IndexAsset {
+	var assets []IndexAsset
+	for _, p := range Platforms {
+		if edition, ok := parseEditionFor(tag, assetURL, p); ok {
+			assets = append(assets, IndexAsset{
+				Edition: edition,
+				OS:      p.OS,
+				Arch:    p.Arch,
+				URL:     assetURL,
+				SHA256:  digest,
+			})
+		}
+	}
+	return assets
+}
+
+// tagFromAssetName returns the tag encoded in a release asset file name of
+// the form hugo[_edition]_<version>_<platform>.<ext>, or false if the name is
+// not of that form.
+func tagFromAssetName(name string) (string, bool) {
+	rest, ok := strings.CutPrefix(name, "hugo_")
+	if !ok {
+		return "", false
+	}
+	for _, p := range []string{"extended_withdeploy_", "extended_", "withdeploy_"} {
+		if after, ok := strings.CutPrefix(rest, p); ok {
+			rest = after
+			break
+		}
+	}
+	version, _, ok := strings.Cut(rest, "_")
+	if !ok {
+		return "", false
+	}
+	tag := "v" + version
+	if !semver.IsValid(tag) {
+		return "", false
+	}
+	return tag, true
+}
+
+// FetchChecksums downloads the checksums file at checksumsURL and returns its
+// digests keyed by file name.
+func FetchChecksums(client *http.Client, checksumsURL string) (map[string]string, error) {
+	resp, err := client.Get(checksumsURL)
+	if err != nil {
+		return nil, fmt.Errorf("downloading checksums file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading checksums file: bad status: %s", resp.Status)
+	}
+
+	return ParseChecksums(resp.Body)
+}
+
+// ParseChecksums parses a checksums file, in which each line contains a
+// SHA-256 hex digest followed by a file name, and returns the digests keyed
+// by file name.
+func ParseChecksums(r io.Reader) (map[string]string, error) {
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			checksums[fields[1]] = fields[0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading checksums file: %w", err)
+	}
+	return checksums, nil
+}
+
+// fetchEditionsFromIndex returns the edition download URLs for the asset's
+// tag on platform p from the release index, and records the expected digest
+// of each in a.Checksums.
+func (r *Repository) fetchEditionsFromIndex(a *Asset, p Platform) (map[string]string, error) {
+	editions := map[string]string{}
+	checksums := map[string]string{}
+	for _, rel := range r.index.Releases {
+		if rel.Tag != a.Tag {
+			continue
+		}
+		for _, ia := range rel.Assets {
+			if ia.OS != p.OS || ia.Arch != p.Arch {
+				continue
+			}
+			editions[ia.Edition] = ia.URL
+			if ia.SHA256 != "" {
+				checksums[ia.URL] = ia.SHA256
+			}
+		}
+	}
+	a.Checksums = checksums
+
+	if len(editions) == 0 {
+		return nil, fmt.Errorf("no downloads found for %s %s", a.Tag, p)
+	}
+	return editions, nil
+}
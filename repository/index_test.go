@@ -0,0 +1,314 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v81/github"
+)
+
+const testIndex = `{
+  "schemaVersion": 1,
+  "releases": [
+    {
+      "tag": "v0.152.0",
+      "assets": [
+        {"edition": "standard", "os": "` + runtime.GOOS + `", "arch": "` + runtime.GOARCH + `", "url": "archives/hugo_0.152.0.tar.gz", "sha256": "aaaa"}
+      ]
+    },
+    {
+      "tag": "v0.153.0",
+      "assets": [
+        {"edition": "standard", "os": "` + runtime.GOOS + `", "arch": "` + runtime.GOARCH + `", "url": "archives/hugo_0.153.0.tar.gz", "sha256": "bbbb"},
+        {"edition": "extended", "os": "` + runtime.GOOS + `", "arch": "` + runtime.GOARCH + `", "url": "https://example.org/hugo_extended_0.153.0.tar.gz"},
+        {"edition": "withdeploy", "os": "plan9", "arch": "amd64", "url": "archives/hugo_withdeploy_0.153.0.tar.gz"}
+      ]
+    }
+  ]
+}`
+
+func TestNewIndexRepository(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hugo/index.json" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testIndex))
+	}))
+	defer ts.Close()
+
+	r, err := NewIndexRepository(ts.URL+"/hugo/index.json", ts.Client())
+	if err != nil {
+		t.Fatalf("NewIndexRepository error: %v", err)
+	}
+
+	if want := []string{"v0.153.0", "v0.152.0"}; !slices.Equal(r.tags, want) {
+		t.Fatalf("tags: want %v got %v", want, r.tags)
+	}
+	if r.latestTag != "v0.153.0" {
+		t.Fatalf("latestTag: want v0.153.0 got %s", r.latestTag)
+	}
+
+	a := &Asset{Tag: "v0.153.0"}
+	editions, err := r.FetchEditions(a)
+	if err != nil {
+		t.Fatalf("FetchEditions error: %v", err)
+	}
+	if len(editions) != 2 {
+		t.Fatalf("FetchEditions: want 2 editions got %d: %v", len(editions), editions)
+	}
+	if want := ts.URL + "/hugo/archives/hugo_0.153.0.tar.gz"; editions["standard"] != want {
+		t.Fatalf("standard URL: want %q got %q", want, editions["standard"])
+	}
+	if want := "https://example.org/hugo_extended_0.153.0.tar.gz"; editions["extended"] != want {
+		t.Fatalf("extended URL: want %q got %q", want, editions["extended"])
+	}
+
+	a.Edition = "standard"
+	if err := a.SetURLFromEditions(editions); err != nil {
+		t.Fatalf("SetURLFromEditions error: %v", err)
+	}
+	if a.Checksum != "bbbb" {
+		t.Fatalf("Checksum: want bbbb got %q", a.Checksum)
+	}
+
+	a = &Asset{Tag: "v0.153.0", Checksums: a.Checksums, Edition: "extended"}
+	if err := a.SetURLFromEditions(editions); err != nil {
+		t.Fatalf("SetURLFromEditions error: %v", err)
+	}
+	if a.Checksum != "" {
+		t.Fatalf("Checksum: want empty got %q", a.Checksum)
+	}
+}
+
+func TestNewIndexRepository_BadStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	if _, err := NewIndexRepository(ts.URL+"/index.json", ts.Client()); err == nil {
+		t.Fatal("NewIndexRepository: expected error for HTTP error response")
+	}
+}
+
+func TestReadIndex_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"notJSON", `not json`},
+		{"schemaVersion", `{"schemaVersion": 2, "releases": []}`},
+		{"tag", `{"schemaVersion": 1, "releases": [{"tag": "0.153.0"}]}`},
+		{"edition", `{"schemaVersion": 1, "releases": [{"tag": "v0.153.0", "assets": [{"edition": "huge", "url": "x"}]}]}`},
+		{"url", `{"schemaVersion": 1, "releases": [{"tag": "v0.153.0", "assets": [{"edition": "standard"}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadIndex(strings.NewReader(tt.doc)); err == nil {
+				t.Fatal("ReadIndex: expected error")
+			}
+		})
+	}
+}
+
+func TestGenerateIndexFromDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"hugo_0.153.0_linux-amd64.tar.gz":          "a",
+		"hugo_extended_0.153.0_windows-arm64.zip":  "b",
+		"hugo_0.153.0_darwin-universal.pkg":        "c",
+		"hugo_0.100.0_Linux-64bit.tar.gz":          "d",
+		"hugo_0.153.0_checksums.txt":               "e",
+		"hugo_0.153.0_freebsd-amd64.tar.gz":        "f",
+		"README.md":                                "g",
+		"hugo_extended_0.153.0_linux-amd64.tar.gz": "h",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ix, err := GenerateIndexFromDir(dir, "")
+	if err != nil {
+		t.Fatalf("GenerateIndexFromDir error: %v", err)
+	}
+
+	if want := []string{"v0.153.0", "v0.100.0"}; !slices.Equal(ix.Tags(), want) {
+		t.Fatalf("Tags: want %v got %v", want, ix.Tags())
+	}
+	if ix.Releases[0].Tag != "v0.153.0" {
+		t.Fatalf("releases must be sorted newest first, got %s first", ix.Releases[0].Tag)
+	}
+
+	var got []string
+	for _, rel := range ix.Releases {
+		for _, a := range rel.Assets {
+			got = append(got, rel.Tag+" "+a.Edition+" "+a.OS+"/"+a.Arch+" "+a.URL)
+			if want := digest(files[a.URL]); a.SHA256 != want {
+				t.Errorf("%s: want digest %s got %s", a.URL, want, a.SHA256)
+			}
+		}
+	}
+	slices.Sort(got)
+	want := []string{
+		"v0.100.0 standard linux/amd64 hugo_0.100.0_Linux-64bit.tar.gz",
+		"v0.153.0 extended linux/amd64 hugo_extended_0.153.0_linux-amd64.tar.gz",
+		"v0.153.0 extended windows/arm64 hugo_extended_0.153.0_windows-arm64.zip",
+		"v0.153.0 standard darwin/amd64 hugo_0.153.0_darwin-universal.pkg",
+		"v0.153.0 standard darwin/arm64 hugo_0.153.0_darwin-universal.pkg",
+		"v0.153.0 standard freebsd/amd64 hugo_0.153.0_freebsd-amd64.tar.gz",
+		"v0.153.0 standard linux/amd64 hugo_0.153.0_linux-amd64.tar.gz",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("assets:\nwant %q\ngot  %q", want, got)
+	}
+}
+
+func TestGenerateIndexFromDir_BaseURL(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hugo_0.153.0_linux-amd64.tar.gz"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ix, err := GenerateIndexFromDir(dir, "https://files.example.org/hugo")
+	if err != nil {
+		t.Fatalf("GenerateIndexFromDir error: %v", err)
+	}
+	if want := "https://files.example.org/hugo/hugo_0.153.0_linux-amd64.tar.gz"; ix.Releases[0].Assets[0].URL != want {
+		t.Fatalf("URL: want %q got %q", want, ix.Releases[0].Assets[0].URL)
+	}
+}
+
+func TestGenerateIndexFromDir_Empty(t *testing.T) {
+	if _, err := GenerateIndexFromDir(t.TempDir(), ""); err == nil {
+		t.Fatal("GenerateIndexFromDir: expected error for directory without archives")
+	}
+}
+
+func TestHasArchives(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"hugo_0.153.0_checksums.txt", "hugo_0.153.0_plan9-amd64.tar.gz", "README.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ok, err := HasArchives(dir); err != nil || ok {
+		t.Fatalf("HasArchives: want false, nil got %v, %v", ok, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "hugo_0.153.0_linux-amd64.tar.gz"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := HasArchives(dir); err != nil || !ok {
+		t.Fatalf("HasArchives: want true, nil got %v, %v", ok, err)
+	}
+}
+
+func TestGenerateIndex(t *testing.T) {
+	const archive = "hugo_extended_0.153.0_linux-arm64.tar.gz"
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/gohugoio/hugo/releases":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[
+			  {"tag_name": "v0.153.0", "assets": [
+			    {"name": "` + archive + `", "browser_download_url": "` + ts.URL + `/dl/` + archive + `"},
+			    {"name": "hugo_0.153.0_checksums.txt", "browser_download_url": "` + ts.URL + `/dl/hugo_0.153.0_checksums.txt"}
+			  ]},
+			  {"tag_name": "v0.154.0", "draft": true, "assets": []},
+			  {"tag_name": "v0.53.0", "assets": []}
+			]`))
+		case "/dl/hugo_0.153.0_checksums.txt":
+			_, _ = w.Write([]byte("cccc  " + archive + "\n"))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client := github.NewClient(ts.Client())
+	baseURL, _ := url.Parse(ts.URL + "/")
+	client.BaseURL = baseURL
+
+	ix, err := GenerateIndex(client, "gohugoio", "hugo", ts.Client(), -1)
+	if err != nil {
+		t.Fatalf("GenerateIndex error: %v", err)
+	}
+	if len(ix.Releases) != 1 {
+		t.Fatalf("releases: want 1 got %d", len(ix.Releases))
+	}
+	want := IndexAsset{Edition: "extended", OS: "linux", Arch: "arm64", URL: ts.URL + "/dl/" + archive, SHA256: "cccc"}
+	if got := ix.Releases[0].Assets; len(got) != 1 || got[0] != want {
+		t.Fatalf("assets: want [%v] got %v", want, got)
+	}
+}
+
+func TestTagFromAssetName(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"hugo_0.153.0_linux-amd64.tar.gz", "v0.153.0", true},
+		{"hugo_extended_0.153.0_linux-amd64.tar.gz", "v0.153.0", true},
+		{"hugo_withdeploy_0.153.0_linux-amd64.tar.gz", "v0.153.0", true},
+		{"hugo_extended_withdeploy_0.153.0_linux-amd64.tar.gz", "v0.153.0", true},
+		{"hugo_0.54.0_Linux-64bit.tar.gz", "v0.54.0", true},
+		{"hugo_0.153.0_checksums.txt", "v0.153.0", true},
+		{"hugo_latest_linux-amd64.tar.gz", "", false},
+		{"hugo-0.153.0.tar.gz", "", false},
+		{"README.md", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tagFromAssetName(tt.name)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("tagFromAssetName(%q) = (%q, %v), want (%q, %v)", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	got, err := ParseChecksums(strings.NewReader("aaaa  a.tar.gz\nbbbb  b.zip\nmalformed\n"))
+	if err != nil {
+		t.Fatalf("ParseChecksums error: %v", err)
+	}
+	if len(got) != 2 || got["a.tar.gz"] != "aaaa" || got["b.zip"] != "bbbb" {
+		t.Fatalf("ParseChecksums: got %v", got)
+	}
+}
+
+// digest returns the SHA-256 hex digest of s.
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
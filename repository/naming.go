@@ -0,0 +1,184 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// A namingRule describes the file name suffix of the release asset published
+// for a platform by a range of releases. Release asset file names have the
+// form hugo[_edition]_<version><suffix>.
+type namingRule struct {
+	Since  string // First tag to which the rule applies; empty for no lower bound
+	Before string // First tag to which the rule no longer applies; empty for no upper bound
+	OS     string // Operating system (e.g. freebsd)
+	Arch   string // Architecture (e.g. amd64)
+	Suffix string // Asset file name suffix (e.g. _FreeBSD-64bit.tar.gz)
+}
+
+// namingRules is the list of rules used to match release assets to platforms.
+// Releases before v0.103.0 used descriptive platform names; later releases
+// use Go's GOOS and GOARCH values.
+var namingRules = []namingRule{
+	// darwin: arm64 runs the amd64 build under Rosetta until universal
+	// binaries were published.
+	{"", "v0.103.0", "darwin", "amd64", "_macOS-64bit.tar.gz"},
+	{"", "v0.103.0", "darwin", "arm64", "_macOS-64bit.tar.gz"},
+	{"v0.103.0", "v0.153.0", "darwin", "amd64", "_darwin-universal.tar.gz"},
+	{"v0.103.0", "v0.153.0", "darwin", "arm64", "_darwin-universal.tar.gz"},
+	{"v0.153.0", "", "darwin", "amd64", "_darwin-universal.pkg"},
+	{"v0.153.0", "", "darwin", "arm64", "_darwin-universal.pkg"},
+
+	// dragonfly
+	{"", "v0.103.0", "dragonfly", "amd64", "_DragonFlyBSD-64bit.tar.gz"},
+	{"v0.103.0", "", "dragonfly", "amd64", "_dragonfly-amd64.tar.gz"},
+
+	// freebsd
+	{"", "v0.103.0", "freebsd", "amd64", "_FreeBSD-64bit.tar.gz"},
+	{"", "v0.103.0", "freebsd", "arm", "_FreeBSD-ARM.tar.gz"},
+	{"", "v0.103.0", "freebsd", "arm64", "_FreeBSD-ARM64.tar.gz"},
+	{"v0.103.0", "", "freebsd", "amd64", "_freebsd-amd64.tar.gz"},
+
+	// linux: arm64 builds before v0.103.0 are taken from the Debian package.
+	{"", "v0.103.0", "linux", "amd64", "_Linux-64bit.tar.gz"},
+	{"", "v0.103.0", "linux", "arm", "_Linux-ARM.tar.gz"},
+	{"", "v0.103.0", "linux", "arm64", "_Linux-ARM64.deb"},
+	{"v0.103.0", "", "linux", "amd64", "_linux-amd64.tar.gz"},
+	{"v0.103.0", "", "linux", "arm", "_linux-arm.tar.gz"},
+	{"v0.103.0", "", "linux", "arm64", "_linux-arm64.tar.gz"},
+	{"v0.103.0", "", "linux", "ppc64le", "_linux-ppc64le.tar.gz"},
+	{"v0.103.0", "", "linux", "s390x", "_linux-s390x.tar.gz"},
+
+	// netbsd
+	{"", "v0.103.0", "netbsd", "amd64", "_NetBSD-64bit.tar.gz"},
+	{"", "v0.103.0", "netbsd", "arm", "_NetBSD-ARM.tar.gz"},
+	{"v0.103.0", "", "netbsd", "amd64", "_netbsd-amd64.tar.gz"},
+
+	// openbsd
+	{"", "v0.103.0", "openbsd", "amd64", "_OpenBSD-64bit.tar.gz"},
+	{"", "v0.103.0", "openbsd", "arm", "_OpenBSD-ARM.tar.gz"},
+	{"", "v0.103.0", "openbsd", "arm64", "_OpenBSD-ARM64.tar.gz"},
+	{"v0.103.0", "", "openbsd", "amd64", "_openbsd-amd64.tar.gz"},
+
+	// solaris
+	{"v0.103.0", "", "solaris", "amd64", "_solaris-amd64.tar.gz"},
+
+	// windows: arm64 runs the amd64 build under emulation until arm64 builds
+	// were published with the new naming scheme.
+	{"", "v0.103.0", "windows", "amd64", "_Windows-64bit.zip"},
+	{"", "v0.103.0", "windows", "arm64", "_Windows-64bit.zip"},
+	{"v0.103.0", "", "windows", "amd64", "_windows-amd64.zip"},
+	{"v0.103.0", "", "windows", "arm64", "_windows-arm64.zip"},
+}
+
+// Platforms is the list of platforms for which release assets are matched,
+// sorted by operating system and architecture.
+var Platforms = rulePlatforms(namingRules)
+
+// rulePlatforms returns the sorted list of distinct platforms in rules.
+func rulePlatforms(rules []namingRule) []Platform {
+	var platforms []Platform
+	for _, r := range rules {
+		p := Platform{OS: r.OS, Arch: r.Arch}
+		if !slices.Contains(platforms, p) {
+			platforms = append(platforms, p)
+		}
+	}
+	slices.SortFunc(platforms, func(a, b Platform) int {
+		return cmp.Or(cmp.Compare(a.OS, b.OS), cmp.Compare(a.Arch, b.Arch))
+	})
+	return platforms
+}
+
+// applies reports whether the rule applies to tag on platform p.
+func (r namingRule) applies(tag string, p Platform) bool {
+	if r.OS != p.OS || r.Arch != p.Arch {
+		return false
+	}
+	if r.Since != "" && semver.Compare(tag, r.Since) == -1 {
+		return false
+	}
+	if r.Before != "" && semver.Compare(tag, r.Before) != -1 {
+		return false
+	}
+	return true
+}
+
+// AssetName returns the file name of the release asset published by tag for
+// edition and platform p (e.g. hugo_extended_0.153.0_linux-amd64.tar.gz), or
+// false if no asset is published for that platform.
+func AssetName(tag, edition string, p Platform) (string, bool) {
+	suffix, ok := assetSuffix(tag, p)
+	if !ok {
+		return "", false
+	}
+	prefix := "hugo_"
+	if edition != "standard" {
+		prefix += edition + "_"
+	}
+	return prefix + strings.TrimPrefix(tag, "v") + suffix, true
+}
+
+// Releases from perEditionChecksumsSince up to, but not including,
+// perEditionChecksumsBefore published a checksums file for each edition other
+// than standard (e.g. hugo_extended_0.55.0_checksums.txt).
+const (
+	perEditionChecksumsSince  = "v0.54.0"
+	perEditionChecksumsBefore = "v0.56.0"
+)
+
+// ChecksumsName returns the file name of the checksums file, published by
+// tag, that lists the release assets of edition (e.g.
+// hugo_0.153.0_checksums.txt).
+func ChecksumsName(tag, edition string) string {
+	prefix := "hugo_"
+	if edition != "standard" && semver.Compare(tag, perEditionChecksumsSince) >= 0 && semver.Compare(tag, perEditionChecksumsBefore) < 0 {
+		prefix += edition + "_"
+	}
+	return prefix + strings.TrimPrefix(tag, "v") + "_checksums.txt"
+}
+
+// ParseAssetName returns the tag and edition of the release asset with file
+// name name (e.g. hugo_extended_0.153.0_linux-amd64.tar.gz) published for
+// platform p, or false if name is not the name of such an asset.
+func ParseAssetName(name string, p Platform) (tag, edition string, ok bool) {
+	tag, ok = tagFromAssetName(name)
+	if !ok {
+		return "", "", false
+	}
+	edition, ok = parseEditionFor(tag, name, p)
+	if !ok {
+		return "", "", false
+	}
+	return tag, edition, true
+}
+
+// assetSuffix returns the expected release asset filename suffix for tag on
+// platform p, or false if no asset is published for that platform.
+func assetSuffix(tag string, p Platform) (string, bool) {
+	for _, r := range namingRules {
+		if r.applies(tag, p) {
+			return r.Suffix, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2026 Veriphor LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/semver"
+)
+
+// TestParseEditionFor_Historical matches real release asset names against
+// every supported platform and verifies that each asset is used on exactly
+// the expected platforms.
+func TestParseEditionFor_Historical(t *testing.T) {
+	darwin := []Platform{{"darwin", "amd64"}, {"darwin", "arm64"}}
+	windows := []Platform{{"windows", "amd64"}, {"windows", "arm64"}}
+
+	tests := []struct {
+		tag       string
+		name      string
+		edition   string
+		platforms []Platform
+	}{
+		// v0.54.0: per-edition checksums files, no arm64 builds for BSD
+		{"v0.54.0", "hugo_0.54.0_DragonFlyBSD-64bit.tar.gz", "standard", []Platform{{"dragonfly", "amd64"}}},
+		{"v0.54.0", "hugo_0.54.0_FreeBSD-32bit.tar.gz", "", nil},
+		{"v0.54.0", "hugo_0.54.0_FreeBSD-64bit.tar.gz", "standard", []Platform{{"freebsd", "amd64"}}},
+		{"v0.54.0", "hugo_0.54.0_FreeBSD-ARM.tar.gz", "standard", []Platform{{"freebsd", "arm"}}},
+		{"v0.54.0", "hugo_0.54.0_Linux-32bit.deb", "", nil},
+		{"v0.54.0", "hugo_0.54.0_Linux-32bit.tar.gz", "", nil},
+		{"v0.54.0", "hugo_0.54.0_Linux-64bit.deb", "", nil},
+		{"v0.54.0", "hugo_0.54.0_Linux-64bit.tar.gz", "standard", []Platform{{"linux", "amd64"}}},
+		{"v0.54.0", "hugo_0.54.0_Linux-ARM.deb", "", nil},
+		{"v0.54.0", "hugo_0.54.0_Linux-ARM.tar.gz", "standard", []Platform{{"linux", "arm"}}},
+		{"v0.54.0", "hugo_0.54.0_Linux-ARM64.deb", "standard", []Platform{{"linux", "arm64"}}},
+		{"v0.54.0", "hugo_0.54.0_Linux-ARM64.tar.gz", "", nil},
+		{"v0.54.0", "hugo_0.54.0_macOS-32bit.tar.gz", "", nil},
+		{"v0.54.0", "hugo_0.54.0_macOS-64bit.tar.gz", "standard", darwin},
+		{"v0.54.0", "hugo_0.54.0_NetBSD-32bit.tar.gz", "", nil},
+		{"v0.54.0", "hugo_0.54.0_NetBSD-64bit.tar.gz", "standard", []Platform{{"netbsd", "amd64"}}},
+		{"v0.54.0", "hugo_0.54.0_NetBSD-ARM.tar.gz", "standard", []Platform{{"netbsd", "arm"}}},
+		{"v0.54.0", "hugo_0.54.0_OpenBSD-32bit.tar.gz", "", nil},
+		{"v0.54.0", "hugo_0.54.0_OpenBSD-64bit.tar.gz", "standard", []Platform{{"openbsd", "amd64"}}},
+		{"v0.54.0", "hugo_0.54.0_OpenBSD-ARM.tar.gz", "standard", []Platform{{"openbsd", "arm"}}},
+		{"v0.54.0", "hugo_0.54.0_Windows-32bit.zip", "", nil},
+		{"v0.54.0", "hugo_0.54.0_Windows-64bit.zip", "standard", windows},
+		{"v0.54.0", "hugo_extended_0.54.0_Linux-64bit.deb", "", nil},
+		{"v0.54.0", "hugo_extended_0.54.0_Linux-64bit.tar.gz", "extended", []Platform{{"linux", "amd64"}}},
+		{"v0.54.0", "hugo_extended_0.54.0_macOS-64bit.tar.gz", "extended", darwin},
+		{"v0.54.0", "hugo_extended_0.54.0_Windows-64bit.zip", "extended", windows},
+
+		// v0.101.0: arm64 builds for BSD, macOS, and Windows
+		{"v0.101.0", "hugo_0.101.0_FreeBSD-ARM64.tar.gz", "standard", []Platform{{"freebsd", "arm64"}}},
+		{"v0.101.0", "hugo_0.101.0_Linux-ARM64.deb", "standard", []Platform{{"linux", "arm64"}}},
+		{"v0.101.0", "hugo_0.101.0_macOS-ARM64.tar.gz", "", nil},
+		{"v0.101.0", "hugo_0.101.0_OpenBSD-ARM64.tar.gz", "standard", []Platform{{"openbsd", "arm64"}}},
+		{"v0.101.0", "hugo_0.101.0_Windows-ARM.zip", "", nil},
+		{"v0.101.0", "hugo_0.101.0_Windows-ARM64.zip", "", nil},
+		{"v0.101.0", "hugo_extended_0.101.0_Linux-ARM64.deb", "extended", []Platform{{"linux", "arm64"}}},
+		{"v0.101.0", "hugo_extended_0.101.0_macOS-ARM64.tar.gz", "", nil},
+
+		// v0.102.3: the last release with descriptive platform names
+		{"v0.102.3", "hugo_0.102.3_DragonFlyBSD-64bit.tar.gz", "standard", []Platform{{"dragonfly", "amd64"}}},
+		{"v0.102.3", "hugo_0.102.3_dragonfly-amd64.tar.gz", "", nil},
+		{"v0.102.3", "hugo_extended_0.102.3_Linux-64bit.tar.gz", "extended", []Platform{{"linux", "amd64"}}},
+
+		// v0.103.0: GOOS and GOARCH platform names, universal macOS builds
+		{"v0.103.0", "hugo_0.103.0_darwin-universal.tar.gz", "standard", darwin},
+		{"v0.103.0", "hugo_0.103.0_dragonfly-amd64.tar.gz", "standard", []Platform{{"dragonfly", "amd64"}}},
+		{"v0.103.0", "hugo_0.103.0_freebsd-amd64.tar.gz", "standard", []Platform{{"freebsd", "amd64"}}},
+		{"v0.103.0", "hugo_0.103.0_FreeBSD-64bit.tar.gz", "", nil},
+		{"v0.103.0", "hugo_0.103.0_linux-amd64.deb", "", nil},
+		{"v0.103.0", "hugo_0.103.0_linux-amd64.tar.gz", "standard", []Platform{{"linux", "amd64"}}},
+		{"v0.103.0", "hugo_0.103.0_linux-arm.tar.gz", "standard", []Platform{{"linux", "arm"}}},
+		{"v0.103.0", "hugo_0.103.0_linux-arm64.deb", "", nil},
+		{"v0.103.0", "hugo_0.103.0_linux-arm64.tar.gz", "standard", []Platform{{"linux", "arm64"}}},
+		{"v0.103.0", "hugo_0.103.0_netbsd-amd64.tar.gz", "standard", []Platform{{"netbsd", "amd64"}}},
+		{"v0.103.0", "hugo_0.103.0_openbsd-amd64.tar.gz", "standard", []Platform{{"openbsd", "amd64"}}},
+		{"v0.103.0", "hugo_0.103.0_solaris-amd64.tar.gz", "standard", []Platform{{"solaris", "amd64"}}},
+		{"v0.103.0", "hugo_0.103.0_windows-amd64.zip", "standard", []Platform{{"windows", "amd64"}}},
+		{"v0.103.0", "hugo_0.103.0_windows-arm64.zip", "standard", []Platform{{"windows", "arm64"}}},
+		{"v0.103.0", "hugo_extended_0.103.0_darwin-universal.tar.gz", "extended", darwin},
+		{"v0.103.0", "hugo_extended_0.103.0_linux-amd64.tar.gz", "extended", []Platform{{"linux", "amd64"}}},
+		{"v0.103.0", "hugo_extended_0.103.0_windows-amd64.zip", "extended", []Platform{{"windows", "amd64"}}},
+
+		// v0.152.2: the last release with macOS tarballs
+		{"v0.152.2", "hugo_extended_withdeploy_0.152.2_darwin-universal.tar.gz", "extended_withdeploy", darwin},
+		{"v0.152.2", "hugo_withdeploy_0.152.2_linux-ppc64le.tar.gz", "withdeploy", []Platform{{"linux", "ppc64le"}}},
+
+		// v0.153.0: macOS installer packages
+		{"v0.153.0", "hugo_0.153.0_darwin-universal.pkg", "standard", darwin},
+		{"v0.153.0", "hugo_0.153.0_darwin-universal.tar.gz", "", nil},
+		{"v0.153.0", "hugo_0.153.0_linux-ppc64le.tar.gz", "standard", []Platform{{"linux", "ppc64le"}}},
+		{"v0.153.0", "hugo_0.153.0_linux-s390x.tar.gz", "standard", []Platform{{"linux", "s390x"}}},
+		{"v0.153.0", "hugo_0.153.0_solaris-amd64.tar.gz", "standard", []Platform{{"solaris", "amd64"}}},
+		{"v0.153.0", "hugo_extended_0.153.0_darwin-universal.pkg", "extended", darwin},
+		{"v0.153.0", "hugo_extended_0.153.0_linux-arm64.tar.gz", "extended", []Platform{{"linux", "arm64"}}},
+		{"v0.153.0", "hugo_extended_withdeploy_0.153.0_freebsd-amd64.tar.gz", "extended_withdeploy", []Platform{{"freebsd", "amd64"}}},
+		{"v0.153.0", "hugo_0.153.0_checksums.txt", "", nil},
+		{"v0.153.0", "hugo_0.152.0_linux-amd64.tar.gz", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "https://github.com/gohugoio/hugo/releases/download/" + tt.tag + "/" + tt.name
+
+			var got []Platform
+			for _, p := range Platforms {
+				edition, ok := parseEditionFor(tt.tag, url, p)
+				if !ok {
+					continue
+				}
+				if edition != tt.edition {
+					t.Errorf("parseEditionFor(%s): edition: want %q got %q", p, tt.edition, edition)
+				}
+				got = append(got, p)
+			}
+			if !slices.Equal(got, tt.platforms) {
+				t.Errorf("platforms: want %v got %v", tt.platforms, got)
+			}
+		})
+	}
+}
+
+func TestPlatforms(t *testing.T) {
+	want := []string{
+		"darwin/amd64", "darwin/arm64",
+		"dragonfly/amd64",
+		"freebsd/amd64", "freebsd/arm", "freebsd/arm64",
+		"linux/amd64", "linux/arm", "linux/arm64", "linux/ppc64le", "linux/s390x",
+		"netbsd/amd64", "netbsd/arm",
+		"openbsd/amd64", "openbsd/arm", "openbsd/arm64",
+		"solaris/amd64",
+		"windows/amd64", "windows/arm64",
+	}
+	var got []string
+	for _, p := range Platforms {
+		got = append(got, p.String())
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("Platforms: want %v got %v", want, got)
+	}
+}
+
+func TestNamingRules(t *testing.T) {
+	// Every rule must apply to at least one tag, and the rules for a platform
+	// must not overlap.
+	tags := []string{"v0.54.0", "v0.102.3", "v0.103.0", "v0.152.2", "v0.153.0", "v1.0.0"}
+	used := make([]bool, len(namingRules))
+	for _, p := range Platforms {
+		for _, tag := range tags {
+			n := 0
+			for i, r := range namingRules {
+				if r.applies(tag, p) {
+					used[i] = true
+					n++
+				}
+			}
+			if n > 1 {
+				t.Errorf("%s %s: %d naming rules apply", p, tag, n)
+			}
+		}
+	}
+	for i, r := range namingRules {
+		if r.Since != "" && r.Before != "" && semver.Compare(r.Since, r.Before) >= 0 {
+			t.Errorf("%s/%s %s: empty version range", r.OS, r.Arch, r.Suffix)
+		} else if !used[i] {
+			t.Errorf("%s/%s %s: rule applies to no tag", r.OS, r.Arch, r.Suffix)
+		}
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	tests := []struct {
+		tag, edition string
+		p            Platform
+		want         string
+		ok           bool
+	}{
+		{"v0.153.0", "extended", Platform{"linux", "amd64"}, "hugo_extended_0.153.0_linux-amd64.tar.gz", true},
+		{"v0.153.0", "extended_withdeploy", Platform{"linux", "arm64"}, "hugo_extended_withdeploy_0.153.0_linux-arm64.tar.gz", true},
+		{"v0.153.0", "standard", Platform{"darwin", "arm64"}, "hugo_0.153.0_darwin-universal.pkg", true},
+		{"v0.100.0", "extended", Platform{"linux", "amd64"}, "hugo_extended_0.100.0_Linux-64bit.tar.gz", true},
+		{"v0.100.0", "standard", Platform{"windows", "arm64"}, "hugo_0.100.0_Windows-64bit.zip", true},
+		{"v0.153.0", "standard", Platform{"plan9", "amd64"}, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := AssetName(tt.tag, tt.edition, tt.p)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("AssetName(%s, %s, %s): want %q, %t got %q, %t", tt.tag, tt.edition, tt.p, tt.want, tt.ok, got, ok)
+		}
+		if ok {
+			if ed, _ := parseEditionFor(tt.tag, got, tt.p); ed != tt.edition {
+				t.Errorf("parseEditionFor(AssetName(%s, %s, %s)): want %s got %s", tt.tag, tt.edition, tt.p, tt.edition, ed)
+			}
+		}
+	}
+}
+
+func TestChecksumsName(t *testing.T) {
+	tests := []struct {
+		tag, edition string
+		want         string
+	}{
+		{"v0.153.0", "extended", "hugo_0.153.0_checksums.txt"},
+		{"v0.56.0", "extended", "hugo_0.56.0_checksums.txt"},
+		{"v0.55.6", "extended", "hugo_extended_0.55.6_checksums.txt"},
+		{"v0.55.0", "standard", "hugo_0.55.0_checksums.txt"},
+		{"v0.54.0", "extended", "hugo_extended_0.54.0_checksums.txt"},
+		{"v0.53.0", "extended", "hugo_0.53.0_checksums.txt"},
+	}
+	for _, tt := range tests {
+		if got := ChecksumsName(tt.tag, tt.edition); got != tt.want {
+			t.Errorf("ChecksumsName(%s, %s): want %q got %q", tt.tag, tt.edition, tt.want, got)
+		}
+	}
+}
+
+func TestParseAssetName(t *testing.T) {
+	linuxAMD64 := Platform{"linux", "amd64"}
+	tests := []struct {
+		name        string
+		p           Platform
+		wantTag     string
+		wantEdition string
+		wantOK      bool
+	}{
+		{"hugo_extended_0.153.0_linux-amd64.tar.gz", linuxAMD64, "v0.153.0", "extended", true},
+		{"hugo_0.153.0_linux-amd64.tar.gz", linuxAMD64, "v0.153.0", "standard", true},
+		{"hugo_extended_withdeploy_0.153.0_linux-amd64.tar.gz", linuxAMD64, "v0.153.0", "extended_withdeploy", true},
+		{"hugo_withdeploy_0.153.0_darwin-universal.pkg", Platform{"darwin", "arm64"}, "v0.153.0", "withdeploy", true},
+		{"hugo_0.54.0_Linux-64bit.tar.gz", linuxAMD64, "v0.54.0", "standard", true},
+		{"hugo_extended_0.153.0_windows-amd64.zip", linuxAMD64, "", "", false},
+		{"hugo_extended_0.153.0_linux-amd64.zip", linuxAMD64, "", "", false},
+		{"hugo_0.153.0_checksums.txt", linuxAMD64, "", "", false},
+		{"hugo.tar.gz", linuxAMD64, "", "", false},
+	}
+	for _, tt := range tests {
+		tag, edition, ok := ParseAssetName(tt.name, tt.p)
+		if tag != tt.wantTag || edition != tt.wantEdition || ok != tt.wantOK {
+			t.Errorf("ParseAssetName(%s, %s): want %q, %q, %t got %q, %q, %t", tt.name, tt.p, tt.wantTag, tt.wantEdition, tt.wantOK, tag, edition, ok)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"path/filepath"
@@ -34,7 +35,12 @@ import (
 	"golang.org/x/mod/semver"
 )
 
-// Repository represents a GitHub repository.
+// firstSemverTag is the first tag that was semantically versioned. Earlier
+// tags are ignored.
+const firstSemverTag = "v0.54.0"
+
+// Repository represents a GitHub repository, or a release index that stands
+// in for one.
 type Repository struct {
 	owner        string         // Owner of the GitHub repository
 	name         string         // Name of the GitHub repository
@@ -42,20 +48,62 @@ type Repository struct {
 	latestTag    string         // Latest repository tag
 	client       *github.Client // A GitHub API client
 	cacheDirPath string         // Local cache directory for tag list caching
+	index        *Index         // Release index used instead of the GitHub API, if any
 }
 
 // ValidEditions is the canonical ordered list of Hugo edition names.
 var ValidEditions = []string{"standard", "withdeploy", "extended", "extended_withdeploy"}
 
+// A Platform identifies an operating system and architecture using the
+// runtime.GOOS and runtime.GOARCH values.
+type Platform struct {
+	OS   string // Operating system (e.g. linux)
+	Arch string // Architecture (e.g. amd64)
+}
+
+// String returns the platform in os/arch form (e.g. linux/amd64).
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// HostPlatform returns the platform on which the program is running.
+func HostPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// ParsePlatform returns the platform for the given operating system and
+// architecture, either of which defaults to that of the host if empty. It
+// returns an error if no release assets are matched for the platform.
+func ParsePlatform(goos, goarch string) (Platform, error) {
+	p := HostPlatform()
+	if goos != "" {
+		p.OS = goos
+	}
+	if goarch != "" {
+		p.Arch = goarch
+	}
+	if !slices.Contains(Platforms, p) {
+		names := make([]string, len(Platforms))
+		for i, pp := range Platforms {
+			names[i] = pp.String()
+		}
+		return Platform{}, fmt.Errorf("platform %q is not supported, must be one of %s", p, strings.Join(names, ", "))
+	}
+	return p, nil
+}
+
 // Asset represents a GitHub release asset for a given release, OS, and architecture.
 type Asset struct {
 	ArchiveDirPath  string            // Directory path of the downloaded archive
-	ArchiveExt      string            // Extension of the downloaded archive: pkg, tar.gz, or zip
+	ArchiveExt      string            // Extension of the downloaded archive (e.g. tar.gz, zip)
 	ArchiveFilePath string            // File path of the downloaded archive
 	ArchiveURL      string            // Download URL for this asset
 	ChecksumsURL    string            // Download URL for the specific checksums file to verify this asset
 	ChecksumsURLs   map[string]string // All checksums files for this release, keyed by filename
+	Checksum        string            // Expected SHA-256 hex digest of the archive, when known in advance
+	Checksums       map[string]string // Expected SHA-256 hex digests known in advance, keyed by download URL
 	Edition         string            // Edition of the release asset (e.g. standard, extended)
+	Platform        Platform          // Target platform of the release asset; the zero value is the host platform
 	Tag             string            // User-selected tag associated with the release for this asset
 	ExecName        string            // Name of the executable file
 }
@@ -88,6 +136,15 @@ func NewAsset(execName string) *Asset {
 
 // FetchTags fetches tags associated with recent releases.
 func (r *Repository) FetchTags() error {
+	if r.index != nil {
+		r.tags = r.index.Tags()
+		if len(r.tags) == 0 {
+			return fmt.Errorf("no tags found")
+		}
+		r.latestTag = firstStableTag(r.tags)
+		return nil
+	}
+
 	// Load the cached tag list if available.
 	var cached []string
 	if r.cacheDirPath != "" {
@@ -145,7 +202,7 @@ func (r *Repository) FetchTags() error {
 		for _, tag := range tags {
 			name := tag.GetName()
 			// Tags prior to v0.54.0 were not semantically versioned.
-			if semver.Compare(name, "v0.54.0") >= 0 {
+			if semver.Compare(name, firstSemverTag) >= 0 {
 				tagNames = append(tagNames, name)
 			}
 		}
@@ -214,9 +271,10 @@ func (r *Repository) GetTagFromString(a *Asset, version string) error {
 	return nil
 }
 
-// SelectTag prompts the user to select a tag from a list of recent tags.
+// SelectTag prompts the user to select a tag from a list of recent tags,
+// writing the list and prompt to w.
 // sortAscending determines the sort order, numTagsToDisplay limits the count.
-func (r *Repository) SelectTag(a *Asset, msg string, sortAscending bool, numTagsToDisplay int) error {
+func (r *Repository) SelectTag(w io.Writer, a *Asset, msg string, sortAscending bool, numTagsToDisplay int) error {
 	tags := r.tags
 
 	// Make a copy for sorting operations
@@ -243,27 +301,27 @@ func (r *Repository) SelectTag(a *Asset, msg string, sortAscending bool, numTags
 	}
 
 	// Display tags
-	fmt.Println()
+	fmt.Fprintln(w)
 	for i, tag := range displayTags {
 		// The tags array is zero-based, but choices are one-based.
-		fmt.Printf("%-20s", fmt.Sprintf("%3d) %s", i+1, tag))
+		fmt.Fprintf(w, "%-20s", fmt.Sprintf("%3d) %s", i+1, tag))
 		if (i+1)%4 == 0 {
-			fmt.Print("\n")
+			fmt.Fprint(w, "\n")
 		} else if i == len(displayTags)-1 {
-			fmt.Print("\n")
+			fmt.Fprint(w, "\n")
 		}
 	}
 
 	// Prompt and collect user input
-	fmt.Println()
-	fmt.Printf("%s (Enter to cancel): ", msg)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s (Enter to cancel): ", msg)
 
 	for a.Tag == "" {
 		var rs string
 		fmt.Scanln(&rs)
 
 		if rs == "" {
-			fmt.Println("Canceled.")
+			fmt.Fprintln(w, "Canceled.")
 			return nil
 		}
 
@@ -271,20 +329,25 @@ func (r *Repository) SelectTag(a *Asset, msg string, sortAscending bool, numTags
 		selection := 0
 		_, err := fmt.Sscanf(strings.TrimSpace(rs), "%d", &selection)
 		if err != nil || selection < 1 || selection > len(displayTags) {
-			fmt.Printf("Please enter a number between 1 and %d, or Enter to cancel: ", len(displayTags))
+			fmt.Fprintf(w, "Please enter a number between 1 and %d, or Enter to cancel: ", len(displayTags))
 		} else {
 			a.Tag = displayTags[selection-1]
 		}
 	}
 
-	fmt.Printf("Selected: %s\n", a.Tag)
+	fmt.Fprintf(w, "Selected: %s\n", a.Tag)
 	return nil
 }
 
 // FetchEditions fetches all available edition download URLs for the asset's
-// tag on the current OS and architecture. The returned map is keyed by edition
+// tag on the asset's target platform. The returned map is keyed by edition
 // name (e.g. "standard", "extended", "extended_withdeploy", "withdeploy").
 func (r *Repository) FetchEditions(a *Asset) (map[string]string, error) {
+	p := a.TargetPlatform()
+	if r.index != nil {
+		return r.fetchEditionsFromIndex(a, p)
+	}
+
 	release, _, err := r.client.Repositories.GetReleaseByTag(context.Background(), r.owner, r.name, a.Tag)
 	if err != nil {
 		return nil, err
@@ -299,7 +362,7 @@ func (r *Repository) FetchEditions(a *Asset) (map[string]string, error) {
 			checksumsURLs[base] = url
 			continue
 		}
-		edition, ok := parseEdition(a.Tag, url)
+		edition, ok := parseEditionFor(a.Tag, url, p)
 		if ok {
 			editions[edition] = url
 		}
@@ -307,7 +370,7 @@ func (r *Repository) FetchEditions(a *Asset) (map[string]string, error) {
 	a.ChecksumsURLs = checksumsURLs
 
 	if len(editions) == 0 {
-		return nil, fmt.Errorf("no downloads found for %s %s/%s", a.Tag, runtime.GOOS, runtime.GOARCH)
+		return nil, fmt.Errorf("no downloads found for %s %s", a.Tag, p)
 	}
 	return editions, nil
 }
@@ -315,35 +378,16 @@ func (r *Repository) FetchEditions(a *Asset) (map[string]string, error) {
 // parseEdition returns the edition name for a given asset download URL on the
 // current OS and architecture, or false if the URL does not match.
 func parseEdition(tag, url string) (string, bool) {
+	return parseEditionFor(tag, url, HostPlatform())
+}
+
+// parseEditionFor returns the edition name for a given asset download URL on
+// platform p, or false if the URL does not match.
+func parseEditionFor(tag, url string, p Platform) (string, bool) {
 	version := tag[1:] // strip leading "v"
 
-	// Determine the expected filename suffix for this OS/arch/version.
-	var suffix string
-	switch runtime.GOOS {
-	case "darwin":
-		if semver.Compare(tag, "v0.103.0") == -1 {
-			suffix = "_macOS-64bit.tar.gz"
-		} else if semver.Compare(tag, "v0.153.0") == -1 {
-			suffix = "_darwin-universal.tar.gz"
-		} else {
-			suffix = "_darwin-universal.pkg"
-		}
-	case "windows":
-		if semver.Compare(tag, "v0.103.0") == -1 {
-			suffix = "_Windows-64bit.zip"
-		} else {
-			suffix = "_windows-" + runtime.GOARCH + ".zip"
-		}
-	case "linux":
-		if semver.Compare(tag, "v0.103.0") == -1 {
-			if runtime.GOARCH == "arm64" {
-				return "", false // .deb not supported
-			}
-			suffix = "_Linux-64bit.tar.gz"
-		} else {
-			suffix = "_linux-" + runtime.GOARCH + ".tar.gz"
-		}
-	default:
+	suffix, ok := assetSuffix(tag, p)
+	if !ok {
 		return "", false
 	}
 
@@ -369,37 +413,38 @@ func parseEdition(tag, url string) (string, bool) {
 	return "", false
 }
 
-// SelectEdition prompts the user to select an edition from the given ordered list.
+// SelectEdition prompts the user to select an edition from the given ordered
+// list, writing the list and prompt to w.
 // On return, a.Edition is set to the chosen edition, or empty if cancelled.
-func SelectEdition(a *Asset, msg string, editions []string) error {
-	fmt.Println()
+func SelectEdition(w io.Writer, a *Asset, msg string, editions []string) error {
+	fmt.Fprintln(w)
 	for i, e := range editions {
-		fmt.Printf("%d) %s", i+1, e)
+		fmt.Fprintf(w, "%d) %s", i+1, e)
 		if i < len(editions)-1 {
-			fmt.Print("  ")
+			fmt.Fprint(w, "  ")
 		}
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Println()
-	fmt.Printf("%s (Enter to cancel): ", msg)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s (Enter to cancel): ", msg)
 
 	for a.Edition == "" {
 		var s string
 		fmt.Scanln(&s)
 		if s == "" {
-			fmt.Println("Canceled.")
+			fmt.Fprintln(w, "Canceled.")
 			return nil
 		}
 		n := 0
 		if _, err := fmt.Sscanf(s, "%d", &n); err != nil || n < 1 || n > len(editions) {
-			fmt.Printf("Please enter a number between 1 and %d, or Enter to cancel: ", len(editions))
+			fmt.Fprintf(w, "Please enter a number between 1 and %d, or Enter to cancel: ", len(editions))
 		} else {
 			a.Edition = editions[n-1]
 		}
 	}
 
-	fmt.Printf("Selected: %s\n\n", a.Edition)
+	fmt.Fprintf(w, "Selected: %s\n\n", a.Edition)
 	return nil
 }
 
@@ -409,14 +454,21 @@ func SelectEdition(a *Asset, msg string, editions []string) error {
 func (a *Asset) SetURLFromEditions(editions map[string]string) error {
 	url, ok := editions[a.Edition]
 	if !ok {
-		return fmt.Errorf("edition %q is not available for %s on %s/%s", a.Edition, a.Tag, runtime.GOOS, runtime.GOARCH)
+		return fmt.Errorf("edition %q is not available for %s on %s", a.Edition, a.Tag, a.TargetPlatform())
 	}
 	a.ArchiveURL = url
+	a.Checksum = a.Checksums[url]
 	switch {
 	case strings.HasSuffix(url, ".pkg"):
 		a.ArchiveExt = "pkg"
+	case strings.HasSuffix(url, ".deb"):
+		a.ArchiveExt = "deb"
 	case strings.HasSuffix(url, ".tar.gz"):
 		a.ArchiveExt = "tar.gz"
+	case strings.HasSuffix(url, ".tar.xz"):
+		a.ArchiveExt = "tar.xz"
+	case strings.HasSuffix(url, ".tar.zst"):
+		a.ArchiveExt = "tar.zst"
 	case strings.HasSuffix(url, ".zip"):
 		a.ArchiveExt = "zip"
 	default:
@@ -442,9 +494,23 @@ func (a *Asset) SetURLFromEditions(editions map[string]string) error {
 	return nil
 }
 
+// TargetPlatform returns the platform for which the asset is built.
+func (a *Asset) TargetPlatform() Platform {
+	if a.Platform == (Platform{}) {
+		return HostPlatform()
+	}
+	return a.Platform
+}
+
+// DirPath returns the path of the cache directory for this asset.
+func (a *Asset) DirPath(cachePath string) string {
+	p := a.TargetPlatform()
+	return cache.BuildDirPath(cachePath, p.OS, p.Arch, a.Tag, a.Edition)
+}
+
 // ExecPath returns the path of the executable file for this asset.
 func (a *Asset) ExecPath(cachePath string) string {
-	return filepath.Join(cachePath, a.Tag, a.Edition, a.ExecName)
+	return filepath.Join(a.DirPath(cachePath), a.ExecName)
 }
 
 // SetTagsForTesting sets tags and latestTag directly. For use in tests only.
@@ -18,12 +18,16 @@ package repository
 
 import (
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/go-github/v81/github"
@@ -87,6 +91,92 @@ func TestAssetExecPath(t *testing.T) {
 	}
 }
 
+func TestAssetExecPath_OtherPlatform(t *testing.T) {
+	cacheDir := filepath.Join("tmp", "cache")
+	p := Platform{OS: "windows", Arch: "arm64"}
+	if p == HostPlatform() {
+		p = Platform{OS: "linux", Arch: "amd64"}
+	}
+	a := &Asset{Tag: "v1.2.3", Edition: "extended", ExecName: "hugo.exe", Platform: p}
+	got := a.ExecPath(cacheDir)
+	want := filepath.Join(cacheDir, "platforms", p.OS+"-"+p.Arch, "v1.2.3", "extended", "hugo.exe")
+	if got != want {
+		t.Fatalf("ExecPath: want %q got %q", want, got)
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	host := HostPlatform()
+	tests := []struct {
+		goos, goarch string
+		want         Platform
+		wantErr      bool
+	}{
+		{"", "", host, false},
+		{"windows", "arm64", Platform{"windows", "arm64"}, false},
+		{"darwin", "", Platform{"darwin", host.Arch}, false},
+		{"", "amd64", Platform{host.OS, "amd64"}, false},
+		{"freebsd", "amd64", Platform{"freebsd", "amd64"}, false},
+		{"linux", "s390x", Platform{"linux", "s390x"}, false},
+		{"plan9", "amd64", Platform{}, true},
+		{"linux", "386", Platform{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos+"/"+tt.goarch, func(t *testing.T) {
+			if !slices.Contains(Platforms, host) && (tt.goos == "" || tt.goarch == "") {
+				t.Skip("host platform is not supported")
+			}
+			got, err := ParsePlatform(tt.goos, tt.goarch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePlatform() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParsePlatform(): want %v got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFetchEditions_OtherPlatform(t *testing.T) {
+	const tag = "v0.153.0"
+	names := []string{
+		"hugo_extended_0.153.0_linux-amd64.tar.gz",
+		"hugo_extended_0.153.0_windows-arm64.zip",
+		"hugo_0.153.0_windows-arm64.zip",
+		"hugo_0.153.0_darwin-universal.pkg",
+	}
+	var assets []string
+	for _, name := range names {
+		assets = append(assets, `{"browser_download_url":"https://example.com/`+name+`"}`)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"tag_name":"` + tag + `","assets":[` + strings.Join(assets, ",") + `]}`))
+	}))
+	defer ts.Close()
+
+	client := github.NewClient(ts.Client())
+	baseURL, _ := url.Parse(ts.URL + "/")
+	client.BaseURL = baseURL
+
+	r := &Repository{owner: "gohugoio", name: "hugo", client: client}
+	a := &Asset{Tag: tag, Platform: Platform{OS: "windows", Arch: "arm64"}}
+
+	editions, err := r.FetchEditions(a)
+	if err != nil {
+		t.Fatalf("FetchEditions error: %v", err)
+	}
+	want := map[string]string{
+		"standard": "https://example.com/hugo_0.153.0_windows-arm64.zip",
+		"extended": "https://example.com/hugo_extended_0.153.0_windows-arm64.zip",
+	}
+	if !maps.Equal(editions, want) {
+		t.Fatalf("FetchEditions: want %v got %v", want, editions)
+	}
+}
+
 func TestSetURLFromEditions(t *testing.T) {
 	var assetURL string
 	switch runtime.GOOS {
@@ -116,6 +206,35 @@ func TestSetURLFromEditions(t *testing.T) {
 	}
 }
 
+func TestSetURLFromEditions_ArchiveExt(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/hugo_0.153.0_linux-amd64.tar.gz":   "tar.gz",
+		"https://example.com/hugo_0.153.0_linux-amd64.tar.xz":   "tar.xz",
+		"https://example.com/hugo_0.153.0_linux-amd64.tar.zst":  "tar.zst",
+		"https://example.com/hugo_0.153.0_windows-amd64.zip":    "zip",
+		"https://example.com/hugo_0.153.0_darwin-universal.pkg": "pkg",
+		"https://example.com/hugo_0.100.0_Linux-ARM64.deb":      "deb",
+		"https://example.com/hugo_0.153.0_linux-amd64.rpm":      "",
+	}
+	for url, want := range tests {
+		a := &Asset{Tag: "v0.153.0", Edition: "standard"}
+		err := a.SetURLFromEditions(map[string]string{"standard": url})
+		if want == "" {
+			if err == nil {
+				t.Errorf("%s: expected error", url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: SetURLFromEditions error: %v", url, err)
+			continue
+		}
+		if a.ArchiveExt != want {
+			t.Errorf("%s: ArchiveExt: want %q got %q", url, want, a.ArchiveExt)
+		}
+	}
+}
+
 func TestGetTagFromString(t *testing.T) {
 	r := &Repository{}
 	r.tags = []string{"v1.2.3", "v1.2.2", "v1.2.1"}
@@ -178,7 +297,7 @@ func TestSelectTag_SelectSecond(t *testing.T) {
 	_, _ = fmt.Fprintln(pw, "2")
 	pw.Close()
 
-	err = r.SelectTag(a, "Select a version", false, 3)
+	err = r.SelectTag(io.Discard, a, "Select a version", false, 3)
 	if err != nil {
 		t.Fatalf("SelectTag error: %v", err)
 	}
@@ -204,7 +323,7 @@ func TestSelectTag_Cancel(t *testing.T) {
 	_, _ = fmt.Fprintln(pw, "")
 	pw.Close()
 
-	err = r.SelectTag(a, "Select a version", false, 3)
+	err = r.SelectTag(io.Discard, a, "Select a version", false, 3)
 	if err != nil {
 		t.Fatalf("SelectTag cancel error: %v", err)
 	}
@@ -247,7 +366,7 @@ func TestSelectEdition_Select(t *testing.T) {
 	_, _ = fmt.Fprintln(pw, "3")
 	pw.Close()
 
-	if err := SelectEdition(a, "Select an edition", editions); err != nil {
+	if err := SelectEdition(io.Discard, a, "Select an edition", editions); err != nil {
 		t.Fatalf("SelectEdition error: %v", err)
 	}
 	if a.Edition != "extended" {
@@ -269,7 +388,7 @@ func TestSelectEdition_Cancel(t *testing.T) {
 	_, _ = fmt.Fprintln(pw, "")
 	pw.Close()
 
-	if err := SelectEdition(a, "Select an edition", editions); err != nil {
+	if err := SelectEdition(io.Discard, a, "Select an edition", editions); err != nil {
 		t.Fatalf("SelectEdition cancel error: %v", err)
 	}
 	if a.Edition != "" {
@@ -328,8 +447,12 @@ func TestParseEdition(t *testing.T) {
 		tests = append(tests, tc{"legacy_windows", legacyTag, mkURL(legacyTag, "hugo_"+legacyVer+"_Windows-64bit.zip"), "standard", true})
 	case "linux":
 		if runtime.GOARCH == "arm64" {
-			// Pre-v0.103.0 linux arm64 is unsupported
-			tests = append(tests, tc{"legacy_linux_arm64_unsupported", legacyTag, mkURL(legacyTag, "hugo_"+legacyVer+"_Linux-64bit.tar.gz"), "", false})
+			// Pre-v0.103.0 linux arm64 is published as a Debian package
+			tests = append(tests,
+				tc{"legacy_linux_arm64", legacyTag, mkURL(legacyTag, "hugo_"+legacyVer+"_Linux-ARM64.deb"), "standard", true},
+				tc{"legacy_linux_arm64_extended", legacyTag, mkURL(legacyTag, "hugo_extended_"+legacyVer+"_Linux-ARM64.deb"), "extended", true},
+				tc{"legacy_linux_arm64_tarball", legacyTag, mkURL(legacyTag, "hugo_"+legacyVer+"_Linux-64bit.tar.gz"), "", false},
+			)
 		} else {
 			tests = append(tests, tc{"legacy_linux", legacyTag, mkURL(legacyTag, "hugo_"+legacyVer+"_Linux-64bit.tar.gz"), "standard", true})
 		}
@@ -23,8 +23,10 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/jmooring/hvm/cache"
+	"golang.org/x/mod/semver"
 )
 
 // errCorruptCache is returned by loadTagCache when the cache file exists but
@@ -61,3 +63,31 @@ func loadTagCache(cacheDirPath string) ([]string, error) {
 	}
 	return tc.Tags, nil
 }
+
+// CachedTags returns the cached tag list, newest first, or nil if the tag list
+// has not been cached.
+func CachedTags(cacheDirPath string) ([]string, error) {
+	return loadTagCache(cacheDirPath)
+}
+
+// MergeCachedTags adds tags to the cached tag list, ignoring tags that are
+// already present or are not semantically versioned.
+func MergeCachedTags(cacheDirPath string, tags []string) error {
+	cached, err := loadTagCache(cacheDirPath)
+	if err != nil && !errors.Is(err, errCorruptCache) {
+		return err
+	}
+	merged := slices.Clone(cached)
+	for _, tag := range tags {
+		if semver.IsValid(tag) && !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	if len(merged) == len(cached) {
+		return nil
+	}
+	slices.SortStableFunc(merged, func(a, b string) int {
+		return semver.Compare(b, a)
+	})
+	return saveTagCache(cacheDirPath, merged)
+}
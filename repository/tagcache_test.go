@@ -19,6 +19,7 @@ package repository
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/jmooring/hvm/cache"
@@ -68,3 +69,23 @@ func TestLoadTagCache_Corrupt(t *testing.T) {
 		t.Fatal("loadTagCache on corrupt file should error")
 	}
 }
+
+func TestMergeCachedTags(t *testing.T) {
+	dir := t.TempDir()
+	if err := saveTagCache(dir, []string{"v0.152.0", "v0.150.0"}); err != nil {
+		t.Fatalf("saveTagCache error: %v", err)
+	}
+
+	if err := MergeCachedTags(dir, []string{"v0.153.0", "v0.151.0", "v0.150.0", "latest"}); err != nil {
+		t.Fatalf("MergeCachedTags error: %v", err)
+	}
+
+	got, err := CachedTags(dir)
+	if err != nil {
+		t.Fatalf("CachedTags error: %v", err)
+	}
+	want := []string{"v0.153.0", "v0.152.0", "v0.151.0", "v0.150.0"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("CachedTags: want %v got %v", want, got)
+	}
+}